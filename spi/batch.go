@@ -0,0 +1,86 @@
+package spi
+
+import (
+	"io"
+	"time"
+)
+
+// Segment is one part of a batch of transfers submitted to a BatchDevice.
+//
+// If both Out and In are set then the segment is a full-duplex exchange
+// and the two slices must have the same length. If only one is set then
+// the segment is a write or a read, respectively.
+type Segment struct {
+	Out []byte
+	In  []byte
+
+	// KeepCS requests that chip-select remain asserted after this segment
+	// completes, so that the following segment continues the same
+	// transaction.
+	KeepCS bool
+
+	// SpeedHz, if non-zero, overrides the device's configured maximum
+	// speed for this segment only.
+	SpeedHz uint32
+
+	// Delay is idle time to insert after this segment completes.
+	Delay time.Duration
+}
+
+// BatchDevice is an optional interface implemented by devices that can
+// perform a whole sequence of transfers in a single operation, such as a
+// single ioctl call with an array of spi_ioc_transfer on Linux.
+//
+// This avoids per-call overhead for chips that need many small
+// transactions.
+type BatchDevice interface {
+	Transfer(segments []Segment) error
+}
+
+// Transfer performs the given segments in order on the given device.
+//
+// If d implements BatchDevice then the segments are submitted natively.
+// Otherwise, each segment is performed separately using Exchange, Write
+// or Read. In that case KeepCS cannot be honored, since a plain Device
+// offers no way to express it, and SpeedHz is honored only for exchanges
+// on devices that implement SpeedOverrideDevice. Segments should therefore
+// not rely on those settings for correctness.
+func Transfer(d Device, segments []Segment) error {
+	if bd, ok := d.(BatchDevice); ok {
+		return bd.Transfer(segments)
+	}
+
+	for _, seg := range segments {
+		if err := transferSegment(d, seg); err != nil {
+			return err
+		}
+		if seg.Delay > 0 {
+			time.Sleep(seg.Delay)
+		}
+	}
+	return nil
+}
+
+// transferSegment performs a single segment on a device using the plain
+// Device methods, ignoring KeepCS and Delay.
+func transferSegment(d Device, seg Segment) error {
+	switch {
+	case seg.Out != nil && seg.In != nil:
+		if sd, ok := d.(SpeedOverrideDevice); ok && seg.SpeedHz != 0 {
+			_, err := sd.ExchangeAt(seg.SpeedHz, seg.Out, seg.In)
+			return err
+		}
+		_, err := d.Exchange(seg.Out, seg.In)
+		return err
+	case seg.Out != nil:
+		return writeOnce(d, seg.Out)
+	case seg.In != nil:
+		n, err := d.Read(seg.In)
+		if err == nil && n < len(seg.In) {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,169 @@
+package spi
+
+// BitOrderInspector is an optional interface implemented by devices that
+// can report the bit order they are actually using, which may differ from
+// the one most recently requested with SetBitOrder if the hardware
+// silently ignored it.
+type BitOrderInspector interface {
+	BitOrder() (BitOrder, error)
+}
+
+// MustSupportLSBFirst sets the given device to LsbFirst bit order and
+// verifies that the setting took effect, returning an error that wraps
+// ErrNotSupported if it did not.
+//
+// Many backends cannot transmit least-significant-bit first, and some of
+// them silently ignore SetBitOrder(LsbFirst). Drivers for LSB-first chips
+// can call this during initialization to fail early rather than producing
+// garbage. The setting can only be verified if c implements
+// BitOrderInspector; otherwise this relies on SetBitOrder reporting an
+// error.
+//
+// A caller that receives ErrNotSupported may fall back to reversing the
+// bits of each byte in software and using MsbFirst instead.
+func MustSupportLSBFirst(c Configurator) error {
+	if err := c.SetBitOrder(LsbFirst); err != nil {
+		return err
+	}
+	bi, ok := c.(BitOrderInspector)
+	if !ok || !Capabilities(c).BitOrderInspector {
+		return nil
+	}
+	got, err := bi.BitOrder()
+	if err != nil {
+		return err
+	}
+	if got != LsbFirst {
+		return NotSupported("LsbFirst bit order")
+	}
+	return nil
+}
+
+// reversedBits maps each byte value to the same value with its bit order
+// reversed.
+var reversedBits [256]byte
+
+func init() {
+	for i := range reversedBits {
+		var r byte
+		for b := uint(0); b < 8; b++ {
+			if i&(1<<b) != 0 {
+				r |= 0x80 >> b
+			}
+		}
+		reversedBits[i] = r
+	}
+}
+
+// reverseBits writes each byte of src to dst with its bit order reversed.
+// dst and src may be the same slice.
+func reverseBits(dst, src []byte) {
+	for i, b := range src {
+		dst[i] = reversedBits[b]
+	}
+}
+
+// NewBitReversed returns a Device that reverses the order of the bits
+// within each byte written to and read from d. Used with an underlying
+// device in MsbFirst order, it simulates LsbFirst operation for backends
+// that cannot do so in hardware.
+//
+// The reversal always applies, so the effective bit order is the opposite
+// of whatever the underlying device is configured to use. Configuration
+// calls, including SetBitOrder, are passed through unchanged.
+//
+// Reversal in software costs a table lookup per byte, along with a copy
+// of each buffer written so that the caller's data is not modified. This
+// is negligible for command and register traffic but can be significant
+// for bulk transfers, where native hardware support is preferable.
+func NewBitReversed(d Device) Device {
+	return bitReversed{d}
+}
+
+type bitReversed struct {
+	Device
+}
+
+func (d bitReversed) Unwrap() Device {
+	return d.Device
+}
+
+func (d bitReversed) Write(data []byte) (int, error) {
+	out := make([]byte, len(data))
+	reverseBits(out, data)
+	return d.Device.Write(out)
+}
+
+func (d bitReversed) Read(data []byte) (int, error) {
+	n, err := d.Device.Read(data)
+	in := received(data, n)
+	reverseBits(in, in)
+	return n, err
+}
+
+func (d bitReversed) Exchange(outData []byte, inData []byte) (int, error) {
+	out := make([]byte, len(outData))
+	reverseBits(out, outData)
+	n, err := d.Device.Exchange(out, inData)
+	in := received(inData, n)
+	reverseBits(in, in)
+	return n, err
+}
+
+func (d bitReversed) Request(outData []byte, inData []byte) (int, error) {
+	out := make([]byte, len(outData))
+	reverseBits(out, outData)
+	n, err := d.Device.Request(out, inData)
+	in := received(inData, n)
+	reverseBits(in, in)
+	return n, err
+}
+
+// BitOrderOverrideDevice is an optional interface implemented by devices
+// that can perform an individual transfer in a bit order other than the
+// one set by SetBitOrder, for the rare mixed-protocol chips that need
+// some transactions in each order.
+//
+// ExchangeBitOrder behaves like Exchange except that the transfer uses the
+// given bit order. The order configured with SetBitOrder is not changed
+// and remains the default for other transfers.
+type BitOrderOverrideDevice interface {
+	ExchangeBitOrder(order BitOrder, outData []byte, inData []byte) (n int, err error)
+}
+
+// ExchangeBitOrder performs an exchange on the given device using the
+// given bit order, with the same meaning as
+// BitOrderOverrideDevice.ExchangeBitOrder.
+//
+// If d implements BitOrderOverrideDevice then its native support is used.
+// Otherwise, if d implements BitOrderInspector, this saves the current
+// bit order, sets the requested one, performs the exchange and then
+// restores the saved order, which costs two extra configuration changes
+// per call, or none if the device is already using the requested order.
+// In that case any other transfers on the device made concurrently with
+// the call would use the wrong bit order, so the caller must serialize
+// all use of the device around it. For any other device, it returns an
+// error wrapping ErrNotSupported.
+func ExchangeBitOrder(d Device, order BitOrder, outData []byte, inData []byte) (n int, err error) {
+	if bo, ok := d.(BitOrderOverrideDevice); ok {
+		return bo.ExchangeBitOrder(order, outData, inData)
+	}
+	bi, ok := d.(BitOrderInspector)
+	if !ok || !Capabilities(d).BitOrderInspector {
+		return 0, NotSupported("per-transfer bit order")
+	}
+	prev, err := bi.BitOrder()
+	if err != nil {
+		return 0, err
+	}
+	if prev == order {
+		return d.Exchange(outData, inData)
+	}
+	if err := d.SetBitOrder(order); err != nil {
+		return 0, err
+	}
+	defer func() {
+		err = joinErrors(err, d.SetBitOrder(prev))
+	}()
+	return d.Exchange(outData, inData)
+}
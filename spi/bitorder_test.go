@@ -0,0 +1,49 @@
+package spi_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+func TestBitReversedShortExchange(t *testing.T) {
+	td := testdevice.New()
+	td.Respond([]byte{0x01})
+	d := spi.NewBitReversed(shortDevice{td})
+
+	in := []byte{0x00, 0x01}
+	n, err := d.Exchange([]byte{0x01, 0x02}, in)
+	if err != nil || n != 1 {
+		t.Fatalf("got %d, %v; want 1 and no error", n, err)
+	}
+	if want := []byte{0x80, 0x01}; !bytes.Equal(in, want) {
+		t.Errorf("got [% x]; want [% x], with the unreceived byte untouched", in, want)
+	}
+}
+
+func TestExchangeBitOrderError(t *testing.T) {
+	d := testdevice.FailAfter(0, io.EOF).Only("Exchange")
+	_, err := spi.ExchangeBitOrder(d, spi.LsbFirst, []byte{0x01}, make([]byte, 1))
+	if err != io.EOF {
+		t.Errorf("got %#v; want io.EOF itself", err)
+	}
+	if got, _ := d.BitOrder(); got != spi.MsbFirst {
+		t.Errorf("bit order was not restored")
+	}
+}
+
+func BenchmarkReverseBits(b *testing.B) {
+	src := make([]byte, 4096)
+	for i := range src {
+		src[i] = byte(i)
+	}
+	dst := make([]byte, len(src))
+	b.SetBytes(int64(len(src)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		spi.ReverseBits(dst, src)
+	}
+}
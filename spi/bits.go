@@ -0,0 +1,66 @@
+package spi
+
+import (
+	"math/bits"
+)
+
+// ExtractBits returns the bits of value selected by mask, shifted right so
+// that the lowest bit of the mask becomes bit zero of the result.
+//
+// For example, ExtractBits(0b1011_0100, 0b0011_1000) returns 0b110. The
+// mask would normally select a contiguous field, but need not. A zero
+// mask always produces zero.
+func ExtractBits(value, mask byte) byte {
+	if mask == 0 {
+		return 0
+	}
+	return (value & mask) >> bits.TrailingZeros8(mask)
+}
+
+// ReplaceBits is the inverse of ExtractBits, returning old with the bits
+// selected by mask replaced by the right-aligned field value, for the
+// "modify" step of a read-modify-write update of a register.
+//
+// Bits of old outside of the mask are preserved, and any bits of field
+// that do not fit within the mask once shifted into place are discarded.
+func ReplaceBits(old, mask, field byte) byte {
+	if mask == 0 {
+		return old
+	}
+	return (old &^ mask) | ((field << bits.TrailingZeros8(mask)) & mask)
+}
+
+// ReadBits writes cmd and then reads a single register byte as a single
+// Request, returning the field of that byte selected by mask as described
+// for ExtractBits.
+func ReadBits(d Device, cmd []byte, mask byte) (byte, error) {
+	got, err := WriteThenRead(d, cmd, 1)
+	if err != nil {
+		return 0, err
+	}
+	return ExtractBits(got[0], mask), nil
+}
+
+// UpdateRegister changes only the bits selected by mask in a single-byte
+// register, reading its current value by writing readCmd and reading one
+// byte as a single Request, replacing the masked field with the
+// right-aligned value as described for ReplaceBits, and then writing
+// writeCmd followed by the new value as a single Write. A short write is
+// reported as io.ErrShortWrite.
+//
+// The read and the write are separate transfers, so the update is not
+// atomic: any change the chip makes to the register between the two, such
+// as setting a status flag, is overwritten. Callers sharing the device
+// between goroutines must serialize the whole update themselves, since
+// NewSynchronized alone does not prevent another goroutine's transfers
+// from falling between the read and the write.
+func UpdateRegister(d Device, readCmd, writeCmd []byte, mask, value byte) error {
+	got, err := WriteThenRead(d, readCmd, 1)
+	if err != nil {
+		return err
+	}
+	out := make([]byte, 0, len(writeCmd)+1)
+	out = append(out, writeCmd...)
+	out = append(out, ReplaceBits(got[0], mask, value))
+	return writeOnce(d, out)
+}
@@ -0,0 +1,73 @@
+package spi_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+func TestExtractBits(t *testing.T) {
+	tests := []struct {
+		value, mask, want byte
+	}{
+		{0b1011_0100, 0b0011_1000, 0b110},
+		{0b1011_0100, 0b1111_1111, 0b1011_0100},
+		{0b1011_0100, 0b1000_0000, 0b1},
+		{0b1011_0100, 0b0000_0001, 0b0},
+		{0b1011_0100, 0b1000_0100, 0b10_0001},
+		{0b1011_0100, 0, 0},
+	}
+	for _, test := range tests {
+		if got := spi.ExtractBits(test.value, test.mask); got != test.want {
+			t.Errorf("ExtractBits(%#010b, %#010b) = %#b; want %#b", test.value, test.mask, got, test.want)
+		}
+	}
+}
+
+func TestReplaceBits(t *testing.T) {
+	tests := []struct {
+		old, mask, field, want byte
+	}{
+		{0b1011_0100, 0b0011_1000, 0b001, 0b1000_1100},
+		{0b1011_0100, 0b0011_1000, 0b1111, 0b1011_1100},
+		{0b1011_0100, 0b1111_1111, 0x5a, 0x5a},
+		{0b1011_0100, 0, 0xff, 0b1011_0100},
+	}
+	for _, test := range tests {
+		got := spi.ReplaceBits(test.old, test.mask, test.field)
+		if got != test.want {
+			t.Errorf("ReplaceBits(%#010b, %#010b, %#b) = %#010b; want %#010b", test.old, test.mask, test.field, got, test.want)
+		}
+		if test.mask != 0 && spi.ExtractBits(got, test.mask) != test.field&spi.ExtractBits(0xff, test.mask) {
+			t.Errorf("ExtractBits does not invert ReplaceBits(%#010b, %#010b, %#b)", test.old, test.mask, test.field)
+		}
+	}
+}
+
+func TestReadBits(t *testing.T) {
+	d := testdevice.New()
+	d.Respond([]byte{0b1011_0100})
+	got, err := spi.ReadBits(d, []byte{0x81}, 0b0011_1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != 0b110 {
+		t.Errorf("ReadBits returned %#b; want 0b110", got)
+	}
+	if want := []byte{0x81}; !bytes.Equal(d.Written(), want) {
+		t.Errorf("wrote [% x]; want [% x]", d.Written(), want)
+	}
+}
+
+func TestUpdateRegister(t *testing.T) {
+	d := testdevice.New()
+	d.Respond([]byte{0b1011_0100})
+	if err := spi.UpdateRegister(d, []byte{0x81}, []byte{0x01}, 0b0011_1000, 0b001); err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte{0x81, 0x01, 0b1000_1100}; !bytes.Equal(d.Written(), want) {
+		t.Errorf("wrote [% x]; want [% x]", d.Written(), want)
+	}
+}
@@ -0,0 +1,230 @@
+package spi
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Bus coordinates several devices that share a single SPI controller, each
+// selected by its own chip-select line.
+//
+// Each device on the bus has its own configuration, but the controller
+// can have only one configuration at a time, so the bus applies a
+// device's configuration to the controller before each of its transfers
+// whenever a different device was used last. All access to the
+// controller from the bus's devices is serialized with a single mutex.
+type Bus struct {
+	mu       sync.Mutex
+	ctrl     Device
+	selectCS func(cs int) error
+	devices  []*busDevice
+
+	// active is the device whose configuration is currently applied to
+	// the controller, or nil if the controller's configuration is not
+	// known to match any device.
+	active *busDevice
+}
+
+// NewBus returns a Bus that performs transfers via the given controller.
+//
+// selectCS is called to route subsequent transfers to the chip-select
+// line with the given index whenever a different device is used. If it is
+// nil and the controller implements ChipSelectSelector then SetChipSelect
+// is used instead. Otherwise, chip-select indexes are ignored, which is
+// appropriate if chip-select is managed some other way, such as by
+// wrapping the controller for each device with WithExternalCS.
+func NewBus(controller Device, selectCS func(cs int) error) *Bus {
+	if selectCS == nil {
+		if sel, ok := controller.(ChipSelectSelector); ok && Capabilities(controller).ChipSelectSelector {
+			selectCS = func(cs int) error {
+				if cs < 0 || cs > 255 {
+					return fmt.Errorf("%w: %d", ErrInvalidChipSelect, cs)
+				}
+				return sel.SetChipSelect(uint8(cs))
+			}
+		}
+	}
+	return &Bus{
+		ctrl:     controller,
+		selectCS: selectCS,
+	}
+}
+
+// Device returns a Device for the device on the given chip-select line,
+// initially using the given configuration. Configuration calls on the
+// returned device change only its own configuration, which takes effect
+// from its next transfer, but an invalid mode or speed is reported
+// immediately, as for SetMode and SetMaxSpeedHz on any other device.
+//
+// Callers must not use the controller directly while any of the bus's
+// devices are in use.
+func (b *Bus) Device(cs int, cfg Config) Device {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	d := &busDevice{
+		bus: b,
+		cs:  cs,
+		cfg: cfg,
+	}
+	b.devices = append(b.devices, d)
+	return d
+}
+
+type busDevice struct {
+	bus *Bus
+	cs  int
+
+	// cfg is guarded by bus.mu.
+	cfg Config
+}
+
+func (d *busDevice) Unwrap() Device {
+	return d.bus.ctrl
+}
+
+func (d *busDevice) SetMode(mode Mode) error {
+	if err := ValidateMode(mode); err != nil {
+		return err
+	}
+	return d.configure(func(cfg *Config) {
+		cfg.Mode = mode
+	})
+}
+
+func (d *busDevice) SetBitOrder(order BitOrder) error {
+	return d.configure(func(cfg *Config) {
+		cfg.BitOrder = order
+	})
+}
+
+func (d *busDevice) SetMaxSpeedHz(speed uint32) error {
+	if err := ValidateSpeed(speed); err != nil {
+		return err
+	}
+	return d.configure(func(cfg *Config) {
+		cfg.MaxSpeedHz = speed
+	})
+}
+
+func (d *busDevice) configure(change func(cfg *Config)) error {
+	d.bus.mu.Lock()
+	defer d.bus.mu.Unlock()
+	change(&d.cfg)
+	if d.bus.active == d {
+		d.bus.active = nil
+	}
+	return nil
+}
+
+func (d *busDevice) Write(data []byte) (int, error) {
+	return d.transfer(func(ctrl Device) (int, error) {
+		return ctrl.Write(data)
+	})
+}
+
+func (d *busDevice) Read(data []byte) (int, error) {
+	return d.transfer(func(ctrl Device) (int, error) {
+		return ctrl.Read(data)
+	})
+}
+
+func (d *busDevice) Exchange(outData []byte, inData []byte) (int, error) {
+	return d.transfer(func(ctrl Device) (int, error) {
+		return ctrl.Exchange(outData, inData)
+	})
+}
+
+func (d *busDevice) Request(outData []byte, inData []byte) (int, error) {
+	return d.transfer(func(ctrl Device) (int, error) {
+		return ctrl.Request(outData, inData)
+	})
+}
+
+// transfer runs the given transfer on the controller with the bus locked,
+// first selecting and configuring for this device if necessary.
+func (d *busDevice) transfer(fn func(ctrl Device) (int, error)) (int, error) {
+	b := d.bus
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.active != d {
+		// If anything below fails then the controller is left in an
+		// unknown state, so no device is considered active.
+		b.active = nil
+		if b.selectCS != nil {
+			if err := b.selectCS(d.cs); err != nil {
+				return 0, err
+			}
+		}
+		if err := Configure(b.ctrl, d.cfg); err != nil {
+			return 0, err
+		}
+		b.active = d
+	}
+	return fn(b.ctrl)
+}
+
+// Validate checks the configurations of all of the bus's devices for
+// mistakes that would otherwise cause data corruption at runtime, so that
+// they can be caught during setup. It returns nil if there are none, or
+// an error describing all of the problems it found.
+//
+// The bus applies a device's mode, bit order, speed and word size to the
+// controller whenever it switches to that device, so those settings can
+// differ freely between chip-selects. The exception is a BitsPerWord of
+// zero, which leaves the word size unchanged and so inherits whichever
+// word size the previous device used; Validate reports this as a conflict
+// if any other device sets a word size other than eight. All other
+// settings, such as 3-wire mode or delays configured directly on the
+// controller, are global to the controller and shared by every device.
+//
+// Validate also reports invalid modes and speeds, word sizes that the
+// controller cannot support, speeds below the minimum reported by a
+// controller implementing SpeedRange, and, when the bus selects devices
+// itself, more than one device on the same chip-select line.
+func (b *Bus) Validate() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	caps := Capabilities(b.ctrl)
+	var errs []error
+	var inherits, setsWordSize bool
+	seen := make(map[int]bool)
+	for _, d := range b.devices {
+		cfg := d.cfg
+		if err := ValidateMode(cfg.Mode); err != nil {
+			errs = append(errs, fmt.Errorf("device on chip-select %d: %w", d.cs, err))
+		}
+		if cfg.BitOrder != MsbFirst && cfg.BitOrder != LsbFirst {
+			errs = append(errs, fmt.Errorf("device on chip-select %d: invalid bit order %s", d.cs, cfg.BitOrder))
+		}
+		if err := ValidateSpeed(cfg.MaxSpeedHz); err != nil {
+			errs = append(errs, fmt.Errorf("device on chip-select %d: %w", d.cs, err))
+		} else if sr, ok := b.ctrl.(SpeedRange); ok && caps.SpeedRange && cfg.MaxSpeedHz != SpeedDefault {
+			if min := sr.MinSpeedHz(); min != 0 && cfg.MaxSpeedHz < min {
+				errs = append(errs, fmt.Errorf("device on chip-select %d: speed %d Hz is below the controller's minimum of %d Hz: %w", d.cs, cfg.MaxSpeedHz, min, ErrSpeedNotSupported))
+			}
+		}
+		switch cfg.BitsPerWord {
+		case 0:
+			inherits = true
+		case 8:
+		default:
+			setsWordSize = true
+			if !caps.WordSizeConfigurator {
+				errs = append(errs, fmt.Errorf("device on chip-select %d: word size %d: %w", d.cs, cfg.BitsPerWord, NotSupported("word size")))
+			}
+		}
+		if b.selectCS != nil {
+			if seen[d.cs] {
+				errs = append(errs, fmt.Errorf("%w: more than one device on chip-select %d", ErrInvalidChipSelect, d.cs))
+			}
+			seen[d.cs] = true
+		}
+	}
+	if inherits && setsWordSize {
+		errs = append(errs, fmt.Errorf("%w: some devices set a word size while others leave it unchanged and so inherit it; set BitsPerWord for every device", ErrBusConflict))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,59 @@
+package spi_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+func TestBusDeviceInvalidConfig(t *testing.T) {
+	bus := spi.NewBus(testdevice.New(), nil)
+	d := bus.Device(0, spi.Config{MaxSpeedHz: spi.SpeedDefault})
+	if err := d.SetMode(9); !errors.Is(err, spi.ErrInvalidMode) {
+		t.Errorf("SetMode(9) returned %v; want ErrInvalidMode", err)
+	}
+	if err := d.SetMaxSpeedHz(0); !errors.Is(err, spi.ErrInvalidSpeed) {
+		t.Errorf("SetMaxSpeedHz(0) returned %v; want ErrInvalidSpeed", err)
+	}
+	if _, err := d.Write([]byte{0x01}); err != nil {
+		t.Errorf("device unusable after rejected configuration: %s", err)
+	}
+}
+
+func TestBusValidate(t *testing.T) {
+	selectCS := func(cs int) error { return nil }
+	valid := spi.Config{MaxSpeedHz: 1000000}
+
+	bus := spi.NewBus(testdevice.New(), selectCS)
+	bus.Device(0, valid)
+	bus.Device(1, spi.Config{Mode: spi.Mode3, BitOrder: spi.LsbFirst, MaxSpeedHz: spi.SpeedDefault})
+	if err := bus.Validate(); err != nil {
+		t.Errorf("unexpected error for valid bus: %s", err)
+	}
+
+	tests := []struct {
+		name string
+		cs   []int
+		cfgs []spi.Config
+		want error
+	}{
+		{"duplicate chip-select", []int{0, 0}, []spi.Config{valid, valid}, spi.ErrInvalidChipSelect},
+		{"invalid mode", []int{0}, []spi.Config{{Mode: 9, MaxSpeedHz: 1000000}}, spi.ErrInvalidMode},
+		{"zero speed", []int{0}, []spi.Config{{}}, spi.ErrInvalidSpeed},
+		{"unsupported word size", []int{0}, []spi.Config{{MaxSpeedHz: 1000000, BitsPerWord: 12}}, spi.ErrNotSupported},
+		{"inherited word size", []int{0, 1}, []spi.Config{{MaxSpeedHz: 1000000, BitsPerWord: 12}, valid}, spi.ErrBusConflict},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			bus := spi.NewBus(testdevice.New(), selectCS)
+			for i, cfg := range test.cfgs {
+				bus.Device(test.cs[i], cfg)
+			}
+			if err := bus.Validate(); !errors.Is(err, test.want) {
+				t.Errorf("Validate returned %v; want %v", err, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package spi
+
+import (
+	"io"
+)
+
+// Caps reports which of the optional interfaces in this package a
+// particular device implements, as returned by Capabilities.
+type Caps struct {
+	BatchDevice            bool
+	BitOrderInspector      bool
+	BitOrderOverrideDevice bool
+	ChipSelectConfigurator bool
+	ChipSelectController   bool
+	ChipSelectSelector     bool
+	Closer                 bool
+	CombinedRequester      bool
+	ContextDevice          bool
+	CSPolarityConfigurator bool
+	DeadlineDevice         bool
+	DelayConfigurator      bool
+	Draining               bool
+	DuplexConfigurator     bool
+	Flusher                bool
+	Inspector              bool
+	LaneConfigurator       bool
+	ModeInspector          bool
+	MultiLaneDevice        bool
+	RawModeFlags           bool
+	ReadFillConfigurator   bool
+	ReadWriteRequester     bool
+	ReadyConfigurator      bool
+	Resettable             bool
+	ScatterGatherDevice    bool
+	SpeedInspector         bool
+	SpeedOverrideDevice    bool
+	SpeedRange             bool
+	ThreeWireConfigurator  bool
+	TransferLimits         bool
+	UnidirectionalDevice   bool
+	WordSizeConfigurator   bool
+	ZeroCopyExchanger      bool
+}
+
+// Capabilities returns which of the optional interfaces in this package
+// the given value implements, so that a driver or tool can check once at
+// startup and branch accordingly rather than repeating type assertions.
+//
+// Capabilities can only detect methods at runtime. Backend authors should
+// also assert at compile time that their types implement the interfaces
+// they intend to, which catches a missing method before any code path
+// depends on it:
+//
+//	var _ spi.Device = (*myDevice)(nil)
+//	var _ spi.BatchDevice = (*myDevice)(nil)
+//
+// Capabilities reports only the interfaces that d itself implements, and
+// so generally gives the same answers as type assertions on d would; the
+// exception is ForwardingDevice, described below. It does not
+// unwrap d using Unwrapper, because a wrapper that does not forward an
+// optional interface cannot be used through it even if the wrapped
+// device implements it. Wrappers must therefore forward any optional
+// interfaces that should remain available through them. To see what the
+// innermost backend supports, pass the result of Base instead.
+//
+// For a device that embeds ForwardingDevice, each forwarded interface is
+// reported only if the wrapped device also implements it.
+func Capabilities(d interface{}) Caps {
+	var caps Caps
+	_, caps.BatchDevice = d.(BatchDevice)
+	_, caps.BitOrderInspector = d.(BitOrderInspector)
+	_, caps.BitOrderOverrideDevice = d.(BitOrderOverrideDevice)
+	_, caps.ChipSelectConfigurator = d.(ChipSelectConfigurator)
+	_, caps.ChipSelectController = d.(ChipSelectController)
+	_, caps.ChipSelectSelector = d.(ChipSelectSelector)
+	_, caps.Closer = d.(io.Closer)
+	_, caps.CombinedRequester = d.(CombinedRequester)
+	_, caps.ContextDevice = d.(ContextDevice)
+	_, caps.CSPolarityConfigurator = d.(CSPolarityConfigurator)
+	_, caps.DeadlineDevice = d.(DeadlineDevice)
+	_, caps.DelayConfigurator = d.(DelayConfigurator)
+	_, caps.Draining = d.(Draining)
+	_, caps.DuplexConfigurator = d.(DuplexConfigurator)
+	_, caps.Flusher = d.(Flusher)
+	_, caps.Inspector = d.(Inspector)
+	_, caps.LaneConfigurator = d.(LaneConfigurator)
+	_, caps.ModeInspector = d.(ModeInspector)
+	_, caps.MultiLaneDevice = d.(MultiLaneDevice)
+	_, caps.RawModeFlags = d.(RawModeFlags)
+	_, caps.ReadFillConfigurator = d.(ReadFillConfigurator)
+	_, caps.ReadWriteRequester = d.(ReadWriteRequester)
+	_, caps.ReadyConfigurator = d.(ReadyConfigurator)
+	_, caps.Resettable = d.(Resettable)
+	_, caps.ScatterGatherDevice = d.(ScatterGatherDevice)
+	_, caps.SpeedInspector = d.(SpeedInspector)
+	_, caps.SpeedOverrideDevice = d.(SpeedOverrideDevice)
+	_, caps.SpeedRange = d.(SpeedRange)
+	_, caps.ThreeWireConfigurator = d.(ThreeWireConfigurator)
+	_, caps.TransferLimits = d.(TransferLimits)
+	_, caps.UnidirectionalDevice = d.(UnidirectionalDevice)
+	_, caps.WordSizeConfigurator = d.(WordSizeConfigurator)
+	_, caps.ZeroCopyExchanger = d.(ZeroCopyExchanger)
+
+	// A ForwardingDevice implements the interfaces it forwards only to the
+	// extent that the device it wraps does.
+	if f, ok := d.(forwarder); ok {
+		inner := Capabilities(f.forwardTarget())
+		caps.BitOrderInspector = caps.BitOrderInspector && inner.BitOrderInspector
+		caps.ChipSelectConfigurator = caps.ChipSelectConfigurator && inner.ChipSelectConfigurator
+		caps.ChipSelectController = caps.ChipSelectController && inner.ChipSelectController
+		caps.ChipSelectSelector = caps.ChipSelectSelector && inner.ChipSelectSelector
+		caps.Closer = caps.Closer && inner.Closer
+		caps.CSPolarityConfigurator = caps.CSPolarityConfigurator && inner.CSPolarityConfigurator
+		caps.DeadlineDevice = caps.DeadlineDevice && inner.DeadlineDevice
+		caps.DelayConfigurator = caps.DelayConfigurator && inner.DelayConfigurator
+		caps.Draining = caps.Draining && inner.Draining
+		caps.DuplexConfigurator = caps.DuplexConfigurator && inner.DuplexConfigurator
+		caps.Flusher = caps.Flusher && inner.Flusher
+		caps.Inspector = caps.Inspector && inner.Inspector
+		caps.LaneConfigurator = caps.LaneConfigurator && inner.LaneConfigurator
+		caps.ModeInspector = caps.ModeInspector && inner.ModeInspector
+		caps.RawModeFlags = caps.RawModeFlags && inner.RawModeFlags
+		caps.ReadFillConfigurator = caps.ReadFillConfigurator && inner.ReadFillConfigurator
+		caps.ReadyConfigurator = caps.ReadyConfigurator && inner.ReadyConfigurator
+		caps.Resettable = caps.Resettable && inner.Resettable
+		caps.SpeedInspector = caps.SpeedInspector && inner.SpeedInspector
+		caps.SpeedRange = caps.SpeedRange && inner.SpeedRange
+		caps.ThreeWireConfigurator = caps.ThreeWireConfigurator && inner.ThreeWireConfigurator
+		caps.TransferLimits = caps.TransferLimits && inner.TransferLimits
+		caps.WordSizeConfigurator = caps.WordSizeConfigurator && inner.WordSizeConfigurator
+	}
+	return caps
+}
@@ -0,0 +1,80 @@
+package spi
+
+import (
+	"hash"
+	"sync"
+)
+
+// ChecksummedDevice is a Device that maintains a running checksum of all
+// of the data transferred through it, for burn-in and integrity testing
+// where a test rig compares the checksum after a known sequence of
+// transfers against an expected value.
+//
+// Unlike the per-frame CRC helpers, the checksum is cumulative across
+// the whole session. For each transfer, the bytes written are added to
+// the checksum followed by the bytes read. Data is added whether or not
+// the transfer reports an error, up to the byte count it returns. Since
+// Request reports only the number of bytes read, all of the bytes it
+// was asked to write are included.
+//
+// A ChecksummedDevice is safe for concurrent use if the wrapped device
+// is, but the checksum is then of the transfers in whatever order they
+// happened to complete.
+type ChecksummedDevice struct {
+	ForwardingDevice
+
+	mu   sync.Mutex
+	hash hash.Hash
+}
+
+var _ Device = (*ChecksummedDevice)(nil)
+
+// NewChecksummed returns a ChecksummedDevice wrapping the given device and
+// adding the data transferred to the given hash, which should be newly
+// created or reset. The hash must not be used elsewhere while the device
+// is in use.
+func NewChecksummed(d Device, algo hash.Hash) *ChecksummedDevice {
+	return &ChecksummedDevice{
+		ForwardingDevice: ForwardingDevice{Device: d},
+		hash:             algo,
+	}
+}
+
+// Sum returns the checksum of all of the data transferred so far.
+func (d *ChecksummedDevice) Sum() []byte {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.hash.Sum(nil)
+}
+
+func (d *ChecksummedDevice) Write(data []byte) (int, error) {
+	n, err := d.Device.Write(data)
+	d.add(received(data, n), nil)
+	return n, err
+}
+
+func (d *ChecksummedDevice) Read(data []byte) (int, error) {
+	n, err := d.Device.Read(data)
+	d.add(nil, received(data, n))
+	return n, err
+}
+
+func (d *ChecksummedDevice) Exchange(outData []byte, inData []byte) (int, error) {
+	n, err := d.Device.Exchange(outData, inData)
+	d.add(received(outData, n), received(inData, n))
+	return n, err
+}
+
+func (d *ChecksummedDevice) Request(outData []byte, inData []byte) (int, error) {
+	n, err := d.Device.Request(outData, inData)
+	d.add(outData, received(inData, n))
+	return n, err
+}
+
+func (d *ChecksummedDevice) add(out, in []byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	// hash.Hash documents that Write never returns an error.
+	d.hash.Write(out)
+	d.hash.Write(in)
+}
@@ -0,0 +1,58 @@
+package spi_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+// overreportingDevice claims to have transferred more bytes than it was
+// given, as a misbehaving backend might.
+type overreportingDevice struct {
+	*testdevice.Device
+}
+
+func (d overreportingDevice) Write(data []byte) (int, error) {
+	n, err := d.Device.Write(data)
+	return n + 1, err
+}
+
+func (d overreportingDevice) Read(data []byte) (int, error) {
+	n, err := d.Device.Read(data)
+	return n + 1, err
+}
+
+func (d overreportingDevice) Exchange(outData []byte, inData []byte) (int, error) {
+	n, err := d.Device.Exchange(outData, inData)
+	return n + 1, err
+}
+
+func (d overreportingDevice) Request(outData []byte, inData []byte) (int, error) {
+	n, err := d.Device.Request(outData, inData)
+	return n + 1, err
+}
+
+func TestChecksummed(t *testing.T) {
+	td := testdevice.New()
+	td.Respond([]byte{0xaa, 0xbb})
+	d := spi.NewChecksummed(td, sha256.New())
+	d.Write([]byte{0x01})
+	d.Exchange([]byte{0x02}, make([]byte, 1))
+	d.Read(make([]byte, 1))
+
+	want := sha256.Sum256([]byte{0x01, 0x02, 0xaa, 0xbb})
+	if got := d.Sum(); !bytes.Equal(got, want[:]) {
+		t.Errorf("wrong checksum\ngot:  %x\nwant: %x", got, want)
+	}
+}
+
+func TestChecksummedOverreported(t *testing.T) {
+	d := spi.NewChecksummed(overreportingDevice{testdevice.New()}, sha256.New())
+	d.Write([]byte{0x01})
+	d.Read(make([]byte, 1))
+	d.Exchange([]byte{0x02}, make([]byte, 1))
+	d.Request([]byte{0x03}, make([]byte, 1))
+}
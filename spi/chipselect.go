@@ -0,0 +1,208 @@
+package spi
+
+import (
+	"sync"
+)
+
+// ChipSelectController is an optional interface implemented by devices that
+// allow the caller to manually control the chip-select line.
+//
+// This is useful for transactions that span several calls, such as a
+// command byte sent with Write followed by a burst read with Read, that
+// must all happen while the device is selected. Callers should type-assert
+// for this interface and bracket the sequence of transfers with AssertCS
+// and DeassertCS.
+//
+// While chip-select is manually asserted, implementations must not toggle
+// it themselves as part of the individual Write, Read, Exchange or Request
+// calls; the line remains asserted until DeassertCS is called.
+type ChipSelectController interface {
+	// AssertCS selects the device, and keeps it selected across any
+	// subsequent transfers until DeassertCS is called.
+	AssertCS() error
+
+	// DeassertCS releases the device, returning to the normal behavior
+	// of selecting the device only for the duration of each transfer.
+	DeassertCS() error
+}
+
+// ChipSelectConfigurator is an optional interface implemented by devices
+// whose chip-select line behavior can be configured.
+//
+// It is separate from Configurator so that existing implementations need
+// not support it. Implementations that cannot honor a particular setting
+// return ErrNotSupported.
+type ChipSelectConfigurator interface {
+	// SetChipSelectActiveHigh selects whether the chip-select line is
+	// driven high (true) or low (false, the conventional behavior) to
+	// select the device.
+	SetChipSelectActiveHigh(activeHigh bool) error
+
+	// SetNoChipSelect selects whether transfers proceed without the
+	// controller asserting chip-select at all, corresponding to the
+	// SPI_NO_CS mode flag on Linux. This is for buses with a single
+	// device that is permanently selected.
+	//
+	// While enabled, the controller leaves the line alone entirely, so
+	// AssertCS and DeassertCS have no effect. It is also the appropriate
+	// setting for an underlying device wrapped with WithExternalCS, so
+	// that the controller does not toggle its own chip-select pin while
+	// the wrapper manages the real line.
+	SetNoChipSelect(enabled bool) error
+}
+
+// CSLine is a chip-select line that is controlled separately from the SPI
+// bus itself, such as an arbitrary GPIO pin.
+type CSLine interface {
+	// Assert selects the device.
+	Assert() error
+
+	// Deassert releases the device.
+	Deassert() error
+}
+
+// WithExternalCS returns a Device that asserts the given chip-select line
+// before each transfer on d and deasserts it afterwards, for boards where
+// the SPI controller does not manage the correct chip-select pin.
+//
+// The line is held asserted across the whole of each transfer, including
+// both phases of a Request. Configuration calls are passed through to d
+// without touching the line.
+//
+// The line's electrical polarity is its own concern. If cs also has a
+// SetChipSelectActiveHigh method, with the same meaning as that of
+// ChipSelectConfigurator, then the returned device implements both
+// ChipSelectConfigurator and CSPolarityConfigurator, passing polarity
+// changes to the line rather than to d, whose own chip-select is not the
+// one in use.
+func WithExternalCS(d Device, cs CSLine) Device {
+	ecs := externalCS{
+		Device: d,
+		cs:     cs,
+	}
+	if pc, ok := cs.(csPolarityLine); ok {
+		return &externalCSPolarity{externalCS: ecs, line: pc}
+	}
+	return ecs
+}
+
+type externalCS struct {
+	Device
+	cs CSLine
+}
+
+func (d externalCS) Unwrap() Device {
+	return d.Device
+}
+
+// csPolarityLine is a CSLine whose polarity can be configured.
+type csPolarityLine interface {
+	CSLine
+	SetChipSelectActiveHigh(activeHigh bool) error
+}
+
+// externalCSPolarity is an externalCS whose line's polarity can be
+// configured.
+type externalCSPolarity struct {
+	externalCS
+	line csPolarityLine
+
+	mu         sync.Mutex
+	activeHigh bool
+}
+
+var _ ChipSelectConfigurator = (*externalCSPolarity)(nil)
+var _ CSPolarityConfigurator = (*externalCSPolarity)(nil)
+
+func (d *externalCSPolarity) SetCSPolarity(activeHigh bool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if err := d.line.SetChipSelectActiveHigh(activeHigh); err != nil {
+		return err
+	}
+	d.activeHigh = activeHigh
+	return nil
+}
+
+func (d *externalCSPolarity) CSPolarity() (bool, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.activeHigh, nil
+}
+
+func (d *externalCSPolarity) SetChipSelectActiveHigh(activeHigh bool) error {
+	return d.SetCSPolarity(activeHigh)
+}
+
+func (d *externalCSPolarity) SetNoChipSelect(enabled bool) error {
+	return NotSupported("disabling an external chip-select line")
+}
+
+func (d externalCS) Write(data []byte) (int, error) {
+	return d.selected(func() (int, error) {
+		return d.Device.Write(data)
+	})
+}
+
+func (d externalCS) Read(data []byte) (int, error) {
+	return d.selected(func() (int, error) {
+		return d.Device.Read(data)
+	})
+}
+
+func (d externalCS) Exchange(outData []byte, inData []byte) (int, error) {
+	return d.selected(func() (int, error) {
+		return d.Device.Exchange(outData, inData)
+	})
+}
+
+func (d externalCS) Request(outData []byte, inData []byte) (int, error) {
+	return d.selected(func() (int, error) {
+		return d.Device.Request(outData, inData)
+	})
+}
+
+// selected runs the given transfer with the chip-select line asserted,
+// always deasserting it afterwards even if the transfer fails.
+func (d externalCS) selected(transfer func() (int, error)) (int, error) {
+	if err := d.cs.Assert(); err != nil {
+		return 0, err
+	}
+	n, err := transfer()
+	if derr := d.cs.Deassert(); err == nil {
+		err = derr
+	}
+	return n, err
+}
+
+// CSPolarityConfigurator is an optional interface implemented by devices
+// whose chip-select polarity can be configured and read back, for the
+// few peripherals that expect an active-high chip-select. This is the
+// portable way to make that setting; RawModeFlags remains available for
+// settings that have no portable equivalent.
+//
+// SetCSPolarity has the same effect as SetChipSelectActiveHigh for
+// devices that also implement ChipSelectConfigurator. Implementations
+// that cannot change the polarity return an error wrapping
+// ErrNotSupported.
+type CSPolarityConfigurator interface {
+	SetCSPolarity(activeHigh bool) error
+	CSPolarity() (activeHigh bool, err error)
+}
+
+// ChipSelectSelector is an optional interface implemented by devices that
+// can address several chip-select lines through the same handle, allowing
+// a single Device to talk to several peripherals without reopening.
+//
+// The selected index applies to all subsequent transfers. Implementations
+// return an error wrapping ErrInvalidChipSelect if asked to select an
+// index that does not exist on the controller.
+//
+// If SetNoChipSelect is enabled then no line is asserted at all,
+// regardless of the selected index. A Bus created without its own
+// selection function uses SetChipSelect to select each of its devices, so
+// callers must not change the selection directly while the bus is in use.
+type ChipSelectSelector interface {
+	SetChipSelect(index uint8) error
+	ChipSelect() (uint8, error)
+}
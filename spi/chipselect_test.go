@@ -0,0 +1,80 @@
+package spi_test
+
+import (
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+// gpioLine is a CSLine that records its electrical level and honors a
+// configured polarity.
+type gpioLine struct {
+	activeHigh bool
+	levels     []bool
+}
+
+func (l *gpioLine) Assert() error {
+	l.set(l.activeHigh)
+	return nil
+}
+
+func (l *gpioLine) Deassert() error {
+	l.set(!l.activeHigh)
+	return nil
+}
+
+func (l *gpioLine) SetChipSelectActiveHigh(activeHigh bool) error {
+	l.activeHigh = activeHigh
+	return nil
+}
+
+func (l *gpioLine) set(high bool) {
+	l.levels = append(l.levels, high)
+}
+
+// logicalLine is a CSLine with no configurable polarity.
+type logicalLine struct{}
+
+func (logicalLine) Assert() error   { return nil }
+func (logicalLine) Deassert() error { return nil }
+
+func TestWithExternalCSPolarity(t *testing.T) {
+	line := &gpioLine{}
+	d := spi.WithExternalCS(testdevice.New(), line)
+	pc, ok := d.(spi.CSPolarityConfigurator)
+	if !ok {
+		t.Fatalf("device does not implement CSPolarityConfigurator for a line with polarity")
+	}
+	if err := pc.SetCSPolarity(true); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := pc.CSPolarity(); err != nil || !got {
+		t.Errorf("CSPolarity returned %t, %v; want true and no error", got, err)
+	}
+	if _, err := d.Write([]byte{0x01}); err != nil {
+		t.Fatal(err)
+	}
+	if got := line.levels; len(got) != 2 || !got[0] || got[1] {
+		t.Errorf("wrong line levels %v; want [true false]", got)
+	}
+
+	// ChipSelectConfigurator sets the same polarity.
+	if err := d.(spi.ChipSelectConfigurator).SetChipSelectActiveHigh(false); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := pc.CSPolarity(); got {
+		t.Errorf("CSPolarity still active-high after SetChipSelectActiveHigh(false)")
+	}
+	line.levels = nil
+	d.Write([]byte{0x01})
+	if got := line.levels; len(got) != 2 || got[0] || !got[1] {
+		t.Errorf("wrong line levels %v; want [false true]", got)
+	}
+
+	plain := spi.WithExternalCS(testdevice.New(), logicalLine{})
+	caps := spi.Capabilities(plain)
+	if caps.CSPolarityConfigurator || caps.ChipSelectConfigurator {
+		t.Errorf("device reports polarity support for a line without polarity")
+	}
+}
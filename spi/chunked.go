@@ -0,0 +1,209 @@
+package spi
+
+import (
+	"io"
+)
+
+// TransferLimits is an optional interface implemented by devices whose
+// backend limits the number of bytes that can be moved in a single
+// transfer, such as the Linux spidev driver's bufsiz parameter.
+//
+// MaxTransferBytes returns the largest transfer the backend accepts, or
+// zero if it has no fixed limit. The chunking helpers in this package use
+// it to size their chunks when the caller does not specify a size.
+type TransferLimits interface {
+	MaxTransferBytes() int
+}
+
+// MaxTransferBytes returns the per-transfer limit of the given device if it
+// implements TransferLimits, or zero, meaning unbounded, if not.
+func MaxTransferBytes(d interface{}) int {
+	if l, ok := d.(TransferLimits); ok {
+		if max := l.MaxTransferBytes(); max > 0 {
+			return max
+		}
+	}
+	return 0
+}
+
+// limitChunk returns the chunk size to use for transfers on d, given the
+// size requested by the caller. A request of zero or less selects the
+// device's own limit, and a request larger than that limit is reduced to
+// it. The result is zero only if neither the caller nor the device gives
+// a limit.
+func limitChunk(d interface{}, want int) int {
+	max := MaxTransferBytes(d)
+	if want <= 0 || (max > 0 && want > max) {
+		return max
+	}
+	return want
+}
+
+// NewChunkedWriter returns a writer that splits each write into a sequence
+// of writes of at most maxChunk bytes each on the given device, for
+// backends that limit the size of a single transfer.
+//
+// The returned writer reports the total number of bytes successfully
+// written across all chunks. If writing a chunk fails or is short, it
+// stops and returns the count so far along with the error.
+//
+// If maxChunk is zero or negative, the device's own limit is used if it
+// implements TransferLimits, and otherwise writes are passed through
+// unchanged. A maxChunk larger than the device's limit is reduced to it.
+func NewChunkedWriter(d WritableDevice, maxChunk int) io.Writer {
+	return &chunkedWriter{
+		dev:      d,
+		maxChunk: limitChunk(d, maxChunk),
+	}
+}
+
+type chunkedWriter struct {
+	dev      WritableDevice
+	maxChunk int
+}
+
+func (w *chunkedWriter) Write(data []byte) (int, error) {
+	if w.maxChunk <= 0 {
+		return w.dev.Write(data)
+	}
+
+	n := 0
+	for n < len(data) {
+		end := n + w.maxChunk
+		if end > len(data) {
+			end = len(data)
+		}
+		chunk := data[n:end]
+		cn, err := w.dev.Write(chunk)
+		n += cn
+		if err != nil {
+			return n, err
+		}
+		if cn < len(chunk) {
+			return n, io.ErrShortWrite
+		}
+	}
+	return n, nil
+}
+
+// Flush implements Flusher. The chunked writer writes each chunk
+// immediately and so has nothing of its own to flush, but it flushes the
+// underlying device in case that is buffered.
+func (w *chunkedWriter) Flush() error {
+	return Flush(w.dev)
+}
+
+// defaultChunkSize is the chunk size used by NewReaderFrom when neither
+// the caller nor the device gives one.
+const defaultChunkSize = 4096
+
+// NewReaderFrom returns an io.ReaderFrom that copies data from a reader to
+// the given device in writes of chunk bytes, so that bulk data such as a
+// framebuffer image can be streamed to a device with io.Copy-style code
+// without the caller managing buffers.
+//
+// Each write is a full chunk except possibly the last. If chunk is zero
+// or negative, the device's own limit is used if it implements
+// TransferLimits, and otherwise a default of 4096 bytes. A chunk larger
+// than the device's limit is reduced to it. ReadFrom returns the total
+// number of bytes written to the device, and treats only io.EOF from the
+// reader as the end of the data.
+func NewReaderFrom(d WritableDevice, chunk int) io.ReaderFrom {
+	chunk = limitChunk(d, chunk)
+	if chunk <= 0 {
+		chunk = defaultChunkSize
+	}
+	return &readerFrom{
+		dev:   d,
+		chunk: chunk,
+	}
+}
+
+type readerFrom struct {
+	dev   WritableDevice
+	chunk int
+}
+
+func (rf *readerFrom) ReadFrom(r io.Reader) (int64, error) {
+	buf := make([]byte, rf.chunk)
+	var total int64
+	for {
+		n, rerr := fillChunk(r, buf)
+		for written := 0; written < n; {
+			wn, err := rf.dev.Write(buf[written:n])
+			written += wn
+			total += int64(wn)
+			if err != nil {
+				return total, err
+			}
+			if wn == 0 {
+				return total, io.ErrShortWrite
+			}
+		}
+		switch rerr {
+		case nil:
+			continue
+		case io.EOF:
+			return total, nil
+		default:
+			return total, rerr
+		}
+	}
+}
+
+// fillChunk reads from r until buf is full or r returns an error,
+// returning that error unchanged. Unlike io.ReadFull it does not turn an
+// io.EOF after a partial chunk into io.ErrUnexpectedEOF, so that the
+// caller can distinguish the end of r from r itself reporting truncated
+// input with io.ErrUnexpectedEOF.
+func fillChunk(r io.Reader, buf []byte) (int, error) {
+	n := 0
+	for n < len(buf) {
+		rn, err := r.Read(buf[n:])
+		n += rn
+		if err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// TransactChunks exchanges data with the device in chunks of at most chunk
+// bytes, calling fn after each exchange with the bytes sent and the bytes
+// received during it, for streaming data through a device such as a
+// cryptographic accelerator while processing its results incrementally.
+//
+// The sent slice passed to fn is a view into data, and the received slice
+// is always the same length as it. The received slice is reused for each
+// chunk, so fn must copy any of it that it needs to keep after returning.
+// TransactChunks stops at the first error from either an exchange or fn,
+// returning it.
+//
+// If chunk is zero or negative, the device's own limit is used if it
+// implements TransferLimits, and otherwise a default of 4096 bytes. A
+// chunk larger than the device's limit is reduced to it.
+func TransactChunks(d Device, data []byte, chunk int, fn func(sent, received []byte) error) error {
+	chunk = limitChunk(d, chunk)
+	if chunk <= 0 {
+		chunk = defaultChunkSize
+	}
+	if chunk > len(data) {
+		chunk = len(data)
+	}
+	buf := make([]byte, chunk)
+	for len(data) > 0 {
+		sent := data
+		if len(sent) > chunk {
+			sent = sent[:chunk]
+		}
+		received := buf[:len(sent)]
+		if _, err := d.Exchange(sent, received); err != nil {
+			return err
+		}
+		if err := fn(sent, received); err != nil {
+			return err
+		}
+		data = data[len(sent):]
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package spi_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+func TestReaderFromPartialChunk(t *testing.T) {
+	d := testdevice.New()
+	n, err := spi.NewReaderFrom(d, 4).ReadFrom(bytes.NewReader([]byte{1, 2, 3, 4, 5, 6}))
+	if err != nil || n != 6 {
+		t.Errorf("got %d, %v; want 6 and no error", n, err)
+	}
+	if got, want := d.Written(), []byte{1, 2, 3, 4, 5, 6}; !bytes.Equal(got, want) {
+		t.Errorf("wrote [% x]; want [% x]", got, want)
+	}
+}
+
+func TestReaderFromTruncatedInput(t *testing.T) {
+	r := io.MultiReader(bytes.NewReader([]byte{1, 2}), &errReader{io.ErrUnexpectedEOF})
+	n, err := spi.NewReaderFrom(testdevice.New(), 4).ReadFrom(r)
+	if err != io.ErrUnexpectedEOF || n != 2 {
+		t.Errorf("got %d, %v; want 2 and io.ErrUnexpectedEOF", n, err)
+	}
+}
+
+// errReader is an io.Reader that always fails with err.
+type errReader struct {
+	err error
+}
+
+func (r *errReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}
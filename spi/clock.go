@@ -0,0 +1,39 @@
+package spi
+
+import (
+	"time"
+)
+
+// Clock is a source of time for the helpers in this package that wait or
+// measure elapsed time, such as PollBit and WithTimeout, so that tests can
+// substitute a fake clock and control the passage of time
+// deterministically. Package testdevice provides one such fake.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+
+	// Sleep blocks until the given duration has elapsed.
+	Sleep(d time.Duration)
+
+	// After returns a channel that receives the current time once the
+	// given duration has elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the Clock used by default, which uses the real time as
+// reported by package time.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+func (systemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
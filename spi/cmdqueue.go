@@ -0,0 +1,122 @@
+package spi
+
+import (
+	"io"
+)
+
+// CommandQueue buffers small writes to a device and sends them together
+// as a single larger Write, reducing the per-transfer overhead for
+// write-only workloads such as display drivers issuing many tiny
+// commands.
+//
+// Queued commands are sent in the order they were pushed, once the
+// buffered data reaches the queue's threshold or when Flush is called.
+// Because several commands are coalesced into one transfer, the device
+// must accept back-to-back commands within a single chip-select period.
+//
+// The queue only buffers writes. Reading through the queue's Read and
+// Request methods flushes it first, so that the device sees all of the
+// commands pushed before the read; a driver that reads from the device
+// directly must call Flush itself before each read. A CommandQueue is not
+// safe for concurrent use.
+type CommandQueue struct {
+	dev       WritableDevice
+	threshold int
+	buf       []byte
+}
+
+var _ Flusher = (*CommandQueue)(nil)
+
+// NewCommandQueue returns a CommandQueue that writes to the given device
+// once at least threshold bytes are queued.
+//
+// If threshold is zero or negative, the device's own limit is used if it
+// implements TransferLimits, and otherwise a default of 4096 bytes. A
+// threshold larger than the device's limit is reduced to it.
+func NewCommandQueue(d WritableDevice, threshold int) *CommandQueue {
+	threshold = limitChunk(d, threshold)
+	if threshold <= 0 {
+		threshold = defaultChunkSize
+	}
+	return &CommandQueue{
+		dev:       d,
+		threshold: threshold,
+		buf:       make([]byte, 0, threshold),
+	}
+}
+
+// Push appends cmd to the queue, first writing any queued commands that
+// it would otherwise overflow, and writes the queued commands once they
+// reach the threshold. A command that is on its own at least as long as
+// the threshold is written immediately.
+//
+// The queue keeps its own copy of cmd, so the caller may reuse it once
+// Push returns. If a write fails then Push returns its error, and the
+// commands that were queued are discarded.
+func (q *CommandQueue) Push(cmd []byte) error {
+	if len(q.buf)+len(cmd) > q.threshold {
+		if err := q.send(); err != nil {
+			return err
+		}
+	}
+	if len(cmd) >= q.threshold {
+		return WriteAll(q.dev, cmd)
+	}
+	q.buf = append(q.buf, cmd...)
+	if len(q.buf) >= q.threshold {
+		return q.send()
+	}
+	return nil
+}
+
+// Flush writes all of the queued commands to the device and then flushes
+// the device itself, in case it is buffered too.
+func (q *CommandQueue) Flush() error {
+	if err := q.send(); err != nil {
+		return err
+	}
+	return Flush(q.dev)
+}
+
+// Read writes all of the queued commands to the device and then reads
+// from it. It returns an error wrapping ErrNotSupported if the device
+// cannot be read.
+func (q *CommandQueue) Read(data []byte) (int, error) {
+	r, ok := q.dev.(io.Reader)
+	if !ok {
+		return 0, NotSupported("reading from a write-only device")
+	}
+	if err := q.send(); err != nil {
+		return 0, err
+	}
+	return r.Read(data)
+}
+
+// Request writes all of the queued commands to the device and then makes
+// the given request on it. It returns an error wrapping ErrNotSupported
+// if the device is not a full Device.
+func (q *CommandQueue) Request(outData []byte, inData []byte) (int, error) {
+	d, ok := q.dev.(Device)
+	if !ok {
+		return 0, NotSupported("request on a write-only device")
+	}
+	if err := q.send(); err != nil {
+		return 0, err
+	}
+	return d.Request(outData, inData)
+}
+
+// Len returns the number of bytes currently queued.
+func (q *CommandQueue) Len() int {
+	return len(q.buf)
+}
+
+// send writes any queued commands, leaving the queue empty.
+func (q *CommandQueue) send() error {
+	if len(q.buf) == 0 {
+		return nil
+	}
+	err := WriteAll(q.dev, q.buf)
+	q.buf = q.buf[:0]
+	return err
+}
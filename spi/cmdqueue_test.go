@@ -0,0 +1,38 @@
+package spi_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+func TestCommandQueueThreshold(t *testing.T) {
+	d := testdevice.New()
+	q := spi.NewCommandQueue(d, 4)
+	q.Push([]byte{0x01, 0x02})
+	if got := len(d.Written()); got != 0 {
+		t.Fatalf("wrote %d bytes before reaching the threshold", got)
+	}
+	q.Push([]byte{0x03, 0x04})
+	if got, want := d.Written(), []byte{0x01, 0x02, 0x03, 0x04}; !bytes.Equal(got, want) {
+		t.Errorf("wrote [% x] on reaching the threshold; want [% x]", got, want)
+	}
+	if q.Len() != 0 {
+		t.Errorf("%d bytes still queued", q.Len())
+	}
+}
+
+func TestCommandQueueReadFlushes(t *testing.T) {
+	d := testdevice.New()
+	d.Respond([]byte{0xaa})
+	q := spi.NewCommandQueue(d, 16)
+	q.Push([]byte{0x01})
+	if _, err := q.Read(make([]byte, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := d.Written(), []byte{0x01}; !bytes.Equal(got, want) {
+		t.Errorf("read did not flush the queue: wrote [% x]; want [% x]", got, want)
+	}
+}
@@ -0,0 +1,53 @@
+package spi
+
+import (
+	"io"
+)
+
+// Command is a request to a chip whose protocol is described by a Codec.
+//
+// Chip drivers define their own command types, typically one per opcode,
+// carrying whatever arguments the opcode takes. The only thing the
+// framework needs to know about a command is how many bytes to read in
+// response to it.
+type Command interface {
+	// ResponseLen returns the number of bytes to read after sending the
+	// command, which may be zero.
+	ResponseLen() int
+}
+
+// Response is the decoded result of a Command. Chip drivers define their
+// own response types and type-assert the result of RoundTrip to the type
+// expected for the command they sent.
+type Response interface{}
+
+// Codec translates between commands and responses and their encoding on
+// the wire, for chips whose protocol has a consistent request/response
+// envelope such as an opcode, a length and a payload.
+type Codec interface {
+	// Encode returns the bytes to send for the given command.
+	Encode(cmd Command) []byte
+
+	// Decode interprets the bytes read in response to a command. It
+	// returns an error if they are not a valid response, such as when a
+	// checksum does not match.
+	Decode(data []byte) (Response, error)
+}
+
+// RoundTrip sends the given command using the given codec and returns the
+// decoded response, writing the encoded command and reading
+// cmd.ResponseLen() bytes as a single Request.
+//
+// If the device reads fewer bytes than expected then RoundTrip returns
+// io.ErrUnexpectedEOF without calling Decode.
+func RoundTrip(d Device, c Codec, cmd Command) (Response, error) {
+	in := make([]byte, cmd.ResponseLen())
+	n, err := d.Request(c.Encode(cmd), in)
+	if err != nil {
+		return nil, err
+	}
+	if n < len(in) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return c.Decode(in)
+}
@@ -0,0 +1,76 @@
+package spi
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Combine returns a Device that writes through w and reads through r, for
+// unusual wirings where the two directions use separate channels.
+//
+// Request writes outData to w and then reads inData from r. A true
+// full-duplex transfer is not possible across two channels, so Exchange
+// always returns an error wrapping ErrNotSupported.
+//
+// Configuration calls are applied to both channels. If the setting
+// succeeds on one channel but fails on the other, the channels are left
+// disagreeing and an error is returned that describes which one failed.
+// If both fail, the error describes both failures.
+func Combine(w WritableDevice, r ReadableDevice) Device {
+	return combined{
+		w: w,
+		r: r,
+	}
+}
+
+type combined struct {
+	w WritableDevice
+	r ReadableDevice
+}
+
+func (d combined) SetMode(mode Mode) error {
+	return combineConfig(d.w.SetMode(mode), d.r.SetMode(mode))
+}
+
+func (d combined) SetBitOrder(order BitOrder) error {
+	return combineConfig(d.w.SetBitOrder(order), d.r.SetBitOrder(order))
+}
+
+func (d combined) SetMaxSpeedHz(speed uint32) error {
+	return combineConfig(d.w.SetMaxSpeedHz(speed), d.r.SetMaxSpeedHz(speed))
+}
+
+func combineConfig(wErr, rErr error) error {
+	switch {
+	case wErr != nil && rErr != nil:
+		return errors.Join(
+			fmt.Errorf("write channel: %w", wErr),
+			fmt.Errorf("read channel: %w", rErr),
+		)
+	case wErr != nil:
+		return fmt.Errorf("write channel (read channel succeeded): %w", wErr)
+	case rErr != nil:
+		return fmt.Errorf("read channel (write channel succeeded): %w", rErr)
+	default:
+		return nil
+	}
+}
+
+func (d combined) Write(data []byte) (int, error) {
+	return d.w.Write(data)
+}
+
+func (d combined) Read(data []byte) (int, error) {
+	return d.r.Read(data)
+}
+
+func (d combined) Exchange(outData []byte, inData []byte) (int, error) {
+	return 0, NotSupported("full-duplex exchange across separate channels")
+}
+
+func (d combined) Request(outData []byte, inData []byte) (int, error) {
+	if err := writeOnce(d.w, outData); err != nil {
+		return 0, err
+	}
+	return d.r.Read(inData)
+}
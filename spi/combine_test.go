@@ -0,0 +1,21 @@
+package spi_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+func TestCombineBothChannelsFail(t *testing.T) {
+	wErr := errors.New("write failed")
+	rErr := errors.New("read failed")
+	w := testdevice.FailAfter(0, wErr).Only("SetMode")
+	r := testdevice.FailAfter(0, rErr).Only("SetMode")
+
+	err := spi.Combine(w, r).SetMode(spi.Mode0)
+	if !errors.Is(err, wErr) || !errors.Is(err, rErr) {
+		t.Errorf("error %q does not report both channels", err)
+	}
+}
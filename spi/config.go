@@ -0,0 +1,105 @@
+package spi
+
+// Config is a set of settings to apply to a device all at once using
+// Configure, as an alternative to calling each of the Configurator methods
+// separately.
+type Config struct {
+	Mode     Mode
+	BitOrder BitOrder
+
+	// MaxSpeedHz must be non-zero. Use SpeedDefault to request the
+	// backend's default speed.
+	MaxSpeedHz uint32
+
+	// BitsPerWord, if non-zero, is applied using WordSizeConfigurator.
+	// Zero leaves the device's word size unchanged.
+	BitsPerWord uint8
+}
+
+// Configure applies all of the settings in cfg to the given device,
+// stopping at and returning the first error.
+//
+// Settings are applied in an order that avoids known hazards: the mode is
+// set first and the speed last, since some backends reset the speed when
+// the mode changes.
+//
+// If cfg.BitsPerWord is set to something other than eight and the device
+// does not implement WordSizeConfigurator, Configure returns an error
+// wrapping ErrNotSupported.
+func Configure(c Configurator, cfg Config) error {
+	if err := c.SetMode(cfg.Mode); err != nil {
+		return err
+	}
+	if err := c.SetBitOrder(cfg.BitOrder); err != nil {
+		return err
+	}
+	if cfg.BitsPerWord != 0 {
+		if wc, ok := c.(WordSizeConfigurator); ok && Capabilities(c).WordSizeConfigurator {
+			if err := wc.SetBitsPerWord(cfg.BitsPerWord); err != nil {
+				return err
+			}
+		} else if cfg.BitsPerWord != 8 {
+			return NotSupported("word size")
+		}
+	}
+	return c.SetMaxSpeedHz(cfg.MaxSpeedHz)
+}
+
+// WithConfig applies cfg to the given device for the duration of a call to
+// fn and then restores the device's previous configuration, such as for
+// reading a device ID at a lower speed without leaving the bus slowed for
+// other users.
+//
+// The previous configuration is read back using ModeInspector,
+// BitOrderInspector and SpeedInspector, so c must implement all three;
+// otherwise WithConfig returns an error wrapping ErrNotSupported without
+// calling fn. A speed read back as zero is restored as SpeedDefault. The
+// word size cannot be read back, so if cfg changes it then it is not
+// restored. For devices that cannot report their configuration, use
+// WithConfigRestore with a known previous configuration instead.
+func WithConfig(c Configurator, cfg Config, fn func() error) error {
+	prev, err := currentConfig(c)
+	if err != nil {
+		return err
+	}
+	return WithConfigRestore(c, prev, cfg, fn)
+}
+
+// WithConfigRestore is like WithConfig, but restores the given previous
+// configuration rather than reading it back from the device.
+//
+// The previous configuration is restored even if applying cfg fails
+// partway or fn panics. If fn returns an error then it is returned,
+// joined with any error from restoring the previous configuration.
+func WithConfigRestore(c Configurator, prev Config, cfg Config, fn func() error) (err error) {
+	defer func() {
+		err = joinErrors(err, Configure(c, prev))
+	}()
+	if err := Configure(c, cfg); err != nil {
+		return err
+	}
+	return fn()
+}
+
+// currentConfig reads back the given device's current configuration.
+func currentConfig(c Configurator) (Config, error) {
+	caps := Capabilities(c)
+	if !(caps.ModeInspector && caps.BitOrderInspector && caps.SpeedInspector) {
+		return Config{}, NotSupported("reading back configuration")
+	}
+	var cfg Config
+	var err error
+	if cfg.Mode, err = c.(ModeInspector).Mode(); err != nil {
+		return cfg, err
+	}
+	if cfg.BitOrder, err = c.(BitOrderInspector).BitOrder(); err != nil {
+		return cfg, err
+	}
+	if cfg.MaxSpeedHz, err = c.(SpeedInspector).MaxSpeedHz(); err != nil {
+		return cfg, err
+	}
+	if cfg.MaxSpeedHz == 0 {
+		cfg.MaxSpeedHz = SpeedDefault
+	}
+	return cfg, nil
+}
@@ -0,0 +1,22 @@
+package spi_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+func TestWithConfigRestoreError(t *testing.T) {
+	d := testdevice.New()
+	err := spi.WithConfigRestore(d, spi.Config{Mode: spi.Mode0, MaxSpeedHz: spi.SpeedDefault}, spi.Config{Mode: spi.Mode3, MaxSpeedHz: spi.SpeedDefault}, func() error {
+		return io.EOF
+	})
+	if err != io.EOF {
+		t.Errorf("got %#v; want io.EOF itself", err)
+	}
+	if got, _ := d.Mode(); got != spi.Mode0 {
+		t.Errorf("mode %d was not restored to 0", got)
+	}
+}
@@ -0,0 +1,93 @@
+package spi
+
+import (
+	"context"
+)
+
+// ContextDevice is an optional interface implemented by devices whose
+// transfers can be cancelled using a context.Context.
+//
+// If the context is cancelled before a transfer completes, the methods
+// return the context's error along with the number of bytes that were
+// transferred before cancellation took effect.
+type ContextDevice interface {
+	Device
+
+	// ExchangeContext is like Exchange but can be cancelled via ctx.
+	ExchangeContext(ctx context.Context, outData []byte, inData []byte) (n int, err error)
+
+	// RequestContext is like Request but can be cancelled via ctx.
+	RequestContext(ctx context.Context, outData []byte, inData []byte) (n int, err error)
+}
+
+// NewContextDevice returns a ContextDevice that gives best-effort
+// cancellation support for the given device. If d already implements
+// ContextDevice then it is returned directly.
+//
+// Because a plain Device has no way to interrupt a transfer in progress,
+// the returned ExchangeContext splits each exchange into sub-transfers of
+// at most chunkSize bytes and checks for cancellation between them. Each
+// sub-transfer is a separate transaction on the bus, so this is only
+// appropriate for devices that tolerate chip-select being released
+// between chunks. If chunkSize is zero or negative, exchanges are not
+// split and cancellation is checked only before the transfer begins. If a
+// sub-transfer completes short without an error, ExchangeContext stops
+// there and returns an error wrapping ErrShortTransfer.
+//
+// Requests cannot be split without changing their meaning, so
+// RequestContext checks for cancellation only before the transfer begins.
+func NewContextDevice(d Device, chunkSize int) ContextDevice {
+	if cd, ok := d.(ContextDevice); ok {
+		return cd
+	}
+	return contextDevice{
+		ForwardingDevice: ForwardingDevice{Device: d},
+		chunkSize:        chunkSize,
+	}
+}
+
+type contextDevice struct {
+	ForwardingDevice
+	chunkSize int
+}
+
+func (d contextDevice) ExchangeContext(ctx context.Context, outData []byte, inData []byte) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if d.chunkSize <= 0 || len(outData) != len(inData) {
+		// The wrapped device is responsible for reporting mismatched
+		// buffer lengths.
+		return d.Device.Exchange(outData, inData)
+	}
+
+	n := 0
+	for n < len(outData) {
+		if err := ctx.Err(); err != nil {
+			return n, err
+		}
+		end := n + d.chunkSize
+		if end > len(outData) {
+			end = len(outData)
+		}
+		cn, err := d.Device.Exchange(outData[n:end], inData[n:end])
+		short := cn < end-n
+		n += cn
+		if err != nil {
+			return n, err
+		}
+		if short {
+			// Continuing after a short chunk would loop forever on a
+			// device that makes no progress, so we report it instead.
+			return CheckTransfer("Exchange", n, len(inData), nil)
+		}
+	}
+	return n, nil
+}
+
+func (d contextDevice) RequestContext(ctx context.Context, outData []byte, inData []byte) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return d.Device.Request(outData, inData)
+}
@@ -0,0 +1,26 @@
+package spi_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+)
+
+// stalledDevice is a device whose exchanges never make progress.
+type stalledDevice struct {
+	*unsafeDevice
+}
+
+func (d stalledDevice) Exchange(outData []byte, inData []byte) (int, error) {
+	return 0, nil
+}
+
+func TestContextDeviceNoProgress(t *testing.T) {
+	d := spi.NewContextDevice(stalledDevice{&unsafeDevice{}}, 2)
+	n, err := d.ExchangeContext(context.Background(), make([]byte, 4), make([]byte, 4))
+	if n != 0 || !errors.Is(err, spi.ErrShortTransfer) {
+		t.Errorf("got %d, %v; want 0 and ErrShortTransfer", n, err)
+	}
+}
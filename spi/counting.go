@@ -0,0 +1,86 @@
+package spi
+
+import (
+	"sync/atomic"
+)
+
+// Stats holds counters of the traffic passed through a device created by
+// NewCounting. Its methods may be called at any time, including
+// concurrently with transfers.
+type Stats struct {
+	bytesWritten atomic.Uint64
+	bytesRead    atomic.Uint64
+	exchanges    atomic.Uint64
+	requests     atomic.Uint64
+}
+
+// BytesWritten returns the total number of bytes written to the device by
+// any method.
+func (s *Stats) BytesWritten() uint64 {
+	return s.bytesWritten.Load()
+}
+
+// BytesRead returns the total number of bytes read from the device by any
+// method.
+func (s *Stats) BytesRead() uint64 {
+	return s.bytesRead.Load()
+}
+
+// Exchanges returns the number of calls to Exchange.
+func (s *Stats) Exchanges() uint64 {
+	return s.exchanges.Load()
+}
+
+// Requests returns the number of calls to Request.
+func (s *Stats) Requests() uint64 {
+	return s.requests.Load()
+}
+
+// NewCounting returns a Device that passes all calls through to d while
+// counting the traffic in the returned Stats.
+//
+// Byte counts are based on the counts returned by the underlying device.
+// Since Request reports only a single count, its written bytes are counted
+// only if it succeeds.
+func NewCounting(d Device) (Device, *Stats) {
+	stats := &Stats{}
+	return counting{
+		ForwardingDevice: ForwardingDevice{Device: d},
+		stats:            stats,
+	}, stats
+}
+
+type counting struct {
+	ForwardingDevice
+	stats *Stats
+}
+
+func (d counting) Write(data []byte) (int, error) {
+	n, err := d.Device.Write(data)
+	d.stats.bytesWritten.Add(uint64(n))
+	return n, err
+}
+
+func (d counting) Read(data []byte) (int, error) {
+	n, err := d.Device.Read(data)
+	d.stats.bytesRead.Add(uint64(n))
+	return n, err
+}
+
+func (d counting) Exchange(outData []byte, inData []byte) (int, error) {
+	n, err := d.Device.Exchange(outData, inData)
+	d.stats.exchanges.Add(1)
+	d.stats.bytesWritten.Add(uint64(n))
+	d.stats.bytesRead.Add(uint64(n))
+	return n, err
+}
+
+func (d counting) Request(outData []byte, inData []byte) (int, error) {
+	n, err := d.Device.Request(outData, inData)
+	d.stats.requests.Add(1)
+	if err == nil {
+		d.stats.bytesWritten.Add(uint64(len(outData)))
+	}
+	d.stats.bytesRead.Add(uint64(n))
+	return n, err
+}
@@ -0,0 +1,113 @@
+package spi
+
+import (
+	"fmt"
+	"io"
+)
+
+// CRC7 returns the 7-bit CRC of data using the polynomial x^7 + x^3 + 1
+// with an initial value of zero, as used for SD card commands.
+func CRC7(data []byte) byte {
+	// The CRC is kept in the top seven bits of crc so that it lines up
+	// with each data bit as it is shifted out of the top of b.
+	var crc byte
+	for _, b := range data {
+		for i := 0; i < 8; i++ {
+			if (b^crc)&0x80 != 0 {
+				crc = crc<<1 ^ 0x09<<1
+			} else {
+				crc <<= 1
+			}
+			b <<= 1
+		}
+	}
+	return crc >> 1
+}
+
+// CRC16CCITT returns the 16-bit CRC of data using the CCITT polynomial
+// x^16 + x^12 + x^5 + 1 with an initial value of zero, a variant also
+// known as CRC-16/XMODEM, as used for SD card data blocks.
+func CRC16CCITT(data []byte) uint16 {
+	var crc uint16
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+// AppendCRC7 appends the CRC7 of data to it and returns the extended slice.
+//
+// The CRC occupies the top seven bits of the appended byte and the lowest
+// bit is set, which is the end bit of an SD card command frame.
+func AppendCRC7(data []byte) []byte {
+	return append(data, CRC7(data)<<1|1)
+}
+
+// AppendCRC16CCITT appends the CRC16CCITT of data to it, most significant
+// byte first, and returns the extended slice.
+func AppendCRC16CCITT(data []byte) []byte {
+	crc := CRC16CCITT(data)
+	return append(data, byte(crc>>8), byte(crc))
+}
+
+// VerifyCRC7 checks a frame that ends with a byte in the format produced
+// by AppendCRC7, returning the frame without that byte. It returns
+// ErrCRCMismatch if the frame is too short to contain the CRC or if the
+// CRC does not match. The end bit is not checked.
+func VerifyCRC7(frame []byte) ([]byte, error) {
+	if len(frame) < 1 {
+		return nil, ErrCRCMismatch
+	}
+	data, got := frame[:len(frame)-1], frame[len(frame)-1]
+	if got>>1 != CRC7(data) {
+		return nil, ErrCRCMismatch
+	}
+	return data, nil
+}
+
+// VerifyCRC16CCITT checks a frame that ends with a CRC in the format
+// produced by AppendCRC16CCITT, returning the frame without the CRC. It
+// returns ErrCRCMismatch if the frame is too short to contain the CRC or
+// if the CRC does not match.
+func VerifyCRC16CCITT(frame []byte) ([]byte, error) {
+	if len(frame) < 2 {
+		return nil, ErrCRCMismatch
+	}
+	data := frame[:len(frame)-2]
+	got := uint16(frame[len(frame)-2])<<8 | uint16(frame[len(frame)-1])
+	if got != CRC16CCITT(data) {
+		return nil, ErrCRCMismatch
+	}
+	return data, nil
+}
+
+// RequestWithCRC writes cmd followed by its CRC16CCITT and then reads
+// readLen bytes followed by their CRC16CCITT, as a single Request,
+// returning the bytes read without the CRC.
+//
+// If the CRC read does not match then it returns ErrCRCMismatch, which
+// callers can use to decide to retry. Protocols using other CRCs, such as
+// CRC7 for SD card commands, can be implemented in a similar way using
+// AppendCRC7 and VerifyCRC7.
+func RequestWithCRC(d Device, cmd []byte, readLen int) ([]byte, error) {
+	if readLen < 0 {
+		return nil, fmt.Errorf("invalid read length %d", readLen)
+	}
+	out := AppendCRC16CCITT(append([]byte(nil), cmd...))
+	in := make([]byte, readLen+2)
+	n, err := d.Request(out, in)
+	if err != nil {
+		return nil, err
+	}
+	if n < len(in) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return VerifyCRC16CCITT(in)
+}
@@ -0,0 +1,107 @@
+package spi_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+func TestCRC7(t *testing.T) {
+	tests := []struct {
+		data []byte
+		want byte
+	}{
+		{nil, 0x00},
+		// SD card CMD0 (GO_IDLE_STATE), whose frame ends in 0x95.
+		{[]byte{0x40, 0x00, 0x00, 0x00, 0x00}, 0x4a},
+		// SD card CMD8 (SEND_IF_COND) with the usual argument, whose frame
+		// ends in 0x87.
+		{[]byte{0x48, 0x00, 0x00, 0x01, 0xaa}, 0x43},
+		// SD card CMD17 (READ_SINGLE_BLOCK) for block zero.
+		{[]byte{0x51, 0x00, 0x00, 0x00, 0x00}, 0x2a},
+	}
+	for _, test := range tests {
+		if got := spi.CRC7(test.data); got != test.want {
+			t.Errorf("CRC7([% x]) = %#04x; want %#04x", test.data, got, test.want)
+		}
+	}
+}
+
+func TestCRC16CCITT(t *testing.T) {
+	tests := []struct {
+		data []byte
+		want uint16
+	}{
+		{nil, 0x0000},
+		{[]byte("123456789"), 0x31c3},
+		{[]byte{0x00}, 0x0000},
+		{[]byte{0xff}, 0x1ef0},
+	}
+	for _, test := range tests {
+		if got := spi.CRC16CCITT(test.data); got != test.want {
+			t.Errorf("CRC16CCITT([% x]) = %#06x; want %#06x", test.data, got, test.want)
+		}
+	}
+}
+
+func TestAppendVerifyCRC(t *testing.T) {
+	cmd0 := spi.AppendCRC7([]byte{0x40, 0x00, 0x00, 0x00, 0x00})
+	if got := cmd0[len(cmd0)-1]; got != 0x95 {
+		t.Errorf("CMD0 frame ends in %#04x; want 0x95", got)
+	}
+	if _, err := spi.VerifyCRC7(cmd0); err != nil {
+		t.Errorf("VerifyCRC7 rejected a valid frame: %s", err)
+	}
+	cmd0[1] ^= 0x01
+	if _, err := spi.VerifyCRC7(cmd0); !errors.Is(err, spi.ErrCRCMismatch) {
+		t.Errorf("VerifyCRC7 accepted a corrupt frame")
+	}
+
+	frame := spi.AppendCRC16CCITT([]byte("123456789"))
+	if got := frame[len(frame)-2:]; !bytes.Equal(got, []byte{0x31, 0xc3}) {
+		t.Errorf("CRC16CCITT appended as [% x]; want [31 c3]", got)
+	}
+	data, err := spi.VerifyCRC16CCITT(frame)
+	if err != nil || string(data) != "123456789" {
+		t.Errorf("VerifyCRC16CCITT returned %q, %v", data, err)
+	}
+	if _, err := spi.VerifyCRC16CCITT(frame[:1]); !errors.Is(err, spi.ErrCRCMismatch) {
+		t.Errorf("VerifyCRC16CCITT accepted a frame too short for a CRC")
+	}
+}
+
+func TestRequestWithCRC(t *testing.T) {
+	tests := []struct {
+		name     string
+		response []byte
+		readLen  int
+		want     []byte
+		wantErr  error
+	}{
+		{"valid", spi.AppendCRC16CCITT([]byte{0xaa, 0xbb}), 2, []byte{0xaa, 0xbb}, nil},
+		{"corrupt", []byte{0xaa, 0xbb, 0x00, 0x00}, 2, nil, spi.ErrCRCMismatch},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			d := testdevice.New()
+			d.Respond(test.response)
+			got, err := spi.RequestWithCRC(d, []byte{0x01}, test.readLen)
+			if !errors.Is(err, test.wantErr) {
+				t.Fatalf("got error %v; want %v", err, test.wantErr)
+			}
+			if !bytes.Equal(got, test.want) {
+				t.Errorf("got [% x]; want [% x]", got, test.want)
+			}
+			if want := spi.AppendCRC16CCITT([]byte{0x01}); !bytes.Equal(d.Written(), want) {
+				t.Errorf("wrote [% x]; want [% x]", d.Written(), want)
+			}
+		})
+	}
+
+	if _, err := spi.RequestWithCRC(testdevice.New(), []byte{0x01}, -1); err == nil {
+		t.Errorf("no error for negative read length")
+	}
+}
@@ -0,0 +1,121 @@
+package spi
+
+import (
+	"fmt"
+	"sync"
+)
+
+// DaisyChain returns count logical devices for a chain of identical chips,
+// such as shift registers, connected in series so that data shifted out
+// of each chip's output feeds the next chip's input. Each chip sends and
+// receives frames of frameSize bytes.
+//
+// The first returned device is the chip whose input is connected directly
+// to the controller's MOSI, and the last is the chip whose output is
+// connected to the controller's MISO. Because data shifts through the
+// whole chain, every transfer on any of the logical devices clocks a full
+// frame through every chip. The chain remembers the frame most recently
+// written to each chip and resends it whenever another chip is written,
+// so writing to one logical device leaves the others unchanged. Frames
+// read back are taken from the corresponding position in the chain, which
+// arrives in the reverse of the order the frames were sent.
+//
+// Each Write, Read or Exchange on a logical device must transfer exactly
+// one frame. Request has no meaningful equivalent for a daisy chain, so
+// it returns an error wrapping ErrNotSupported. Configuration calls on any
+// of the logical devices apply to the underlying device and so affect the
+// entire chain.
+//
+// DaisyChain panics if count or frameSize is less than one.
+func DaisyChain(d Device, count int, frameSize int) []Device {
+	if count < 1 || frameSize < 1 {
+		panic("DaisyChain requires at least one device with at least one byte per frame")
+	}
+	chain := &daisyChain{
+		dev:       d,
+		count:     count,
+		frameSize: frameSize,
+		frames:    make([]byte, count*frameSize),
+	}
+	devs := make([]Device, count)
+	for i := range devs {
+		devs[i] = daisyChainDevice{
+			Device: d,
+			chain:  chain,
+			index:  i,
+		}
+	}
+	return devs
+}
+
+type daisyChain struct {
+	mu        sync.Mutex
+	dev       Device
+	count     int
+	frameSize int
+
+	// frames holds the most recent frame written to each chip, in the
+	// order they are sent on the wire: the chip furthest from MOSI first.
+	frames []byte
+}
+
+type daisyChainDevice struct {
+	Device
+	chain *daisyChain
+	index int
+}
+
+func (d daisyChainDevice) Unwrap() Device {
+	return d.chain.dev
+}
+
+func (d daisyChainDevice) Write(data []byte) (int, error) {
+	return d.transfer(data, nil)
+}
+
+func (d daisyChainDevice) Read(data []byte) (int, error) {
+	return d.transfer(nil, data)
+}
+
+func (d daisyChainDevice) Exchange(outData []byte, inData []byte) (int, error) {
+	if err := ValidateExchange(outData, inData); err != nil {
+		return 0, err
+	}
+	return d.transfer(outData, inData)
+}
+
+func (d daisyChainDevice) Request(outData []byte, inData []byte) (int, error) {
+	return 0, NotSupported("Request on a daisy-chained device")
+}
+
+// transfer shifts a full chain's worth of frames through the chain,
+// substituting out as this device's frame if it is non-nil, and copying
+// this device's received frame into in if it is non-nil.
+func (d daisyChainDevice) transfer(out, in []byte) (int, error) {
+	c := d.chain
+	if (out != nil && len(out) != c.frameSize) || (in != nil && len(in) != c.frameSize) {
+		return 0, fmt.Errorf("daisy-chained device transfers must be exactly %d bytes", c.frameSize)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	start := (c.count - 1 - d.index) * c.frameSize
+	frame := c.frames[start : start+c.frameSize]
+	if out != nil {
+		copy(frame, out)
+	}
+	if in == nil {
+		if err := writeOnce(c.dev, c.frames); err != nil {
+			return 0, err
+		}
+		return c.frameSize, nil
+	}
+
+	buf := make([]byte, len(c.frames))
+	if _, err := c.dev.Exchange(c.frames, buf); err != nil {
+		return 0, err
+	}
+	copy(in, buf[start:start+c.frameSize])
+	return c.frameSize, nil
+}
@@ -0,0 +1,59 @@
+package spi_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+func TestDaisyChain(t *testing.T) {
+	d := testdevice.New()
+	chips := spi.DaisyChain(d, 3, 1)
+
+	if _, err := chips[0].Write([]byte{0x0a}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chips[2].Write([]byte{0x0c}); err != nil {
+		t.Fatal(err)
+	}
+	// The first chip's frame is sent last, and each write resends the
+	// frames of the other chips.
+	if want := []byte{0x00, 0x00, 0x0a, 0x0c, 0x00, 0x0a}; !bytes.Equal(d.Written(), want) {
+		t.Errorf("wrote [% x]; want [% x]", d.Written(), want)
+	}
+
+	d.Respond([]byte{0x01, 0x02, 0x03})
+	in := make([]byte, 1)
+	n, err := chips[1].Exchange([]byte{0x0b}, in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || in[0] != 0x02 {
+		t.Errorf("exchange read %d bytes [% x]; want [02]", n, in[:n])
+	}
+	if want := []byte{0x0c, 0x0b, 0x0a}; !bytes.Equal(d.Written()[6:], want) {
+		t.Errorf("exchange wrote [% x]; want [% x]", d.Written()[6:], want)
+	}
+
+	if _, err := chips[0].Write([]byte{0x01, 0x02}); err == nil {
+		t.Errorf("no error for a write longer than one frame")
+	}
+	if _, err := chips[0].Request([]byte{0x01}, in); !errors.Is(err, spi.ErrNotSupported) {
+		t.Errorf("Request returned %v; want ErrNotSupported", err)
+	}
+	if !spi.SameDevice(chips[0], d) {
+		t.Errorf("daisy-chained device does not unwrap to the underlying device")
+	}
+}
+
+func TestDaisyChainInvalid(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("no panic for a chain of zero devices")
+		}
+	}()
+	spi.DaisyChain(testdevice.New(), 0, 1)
+}
@@ -0,0 +1,36 @@
+package spi
+
+import (
+	"time"
+)
+
+// DelayConfigurator is an optional interface implemented by devices that
+// can insert idle time into transfers, for peripherals that need a
+// minimum delay between words or between chip-select changes.
+//
+// On Linux these correspond to the delay fields of spi_ioc_transfer.
+// Hardware can only produce delays in multiples of some resolution, so
+// implementations round any delay up to the nearest achievable value;
+// the delay actually used is never shorter than the one requested.
+//
+// The chip-select setup and hold delays correspond to the cs_setup and
+// cs_hold fields, which are only available on newer Linux kernels.
+// Implementations that cannot set a particular delay return
+// ErrNotSupported.
+type DelayConfigurator interface {
+	// SetWordDelay sets the idle time inserted between consecutive words
+	// of a transfer.
+	SetWordDelay(d time.Duration) error
+
+	// SetCSChangeDelay sets the idle time inserted after a transfer
+	// before chip-select is changed.
+	SetCSChangeDelay(d time.Duration) error
+
+	// SetCSSetupDelay sets the time between asserting chip-select and
+	// the first clock edge of a transfer.
+	SetCSSetupDelay(d time.Duration) error
+
+	// SetCSHoldDelay sets the time between the last clock edge of a
+	// transfer and deasserting chip-select.
+	SetCSHoldDelay(d time.Duration) error
+}
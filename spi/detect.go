@@ -0,0 +1,49 @@
+package spi
+
+import (
+	"errors"
+)
+
+// DetectMode tries each of the four standard modes in turn, from Mode0 to
+// Mode3, probing the device as described for Probe, and returns the first
+// mode in which the device gives the expected response. The device is
+// left configured in that mode. Modes that the backend reports as
+// unsupported are skipped.
+//
+// This is intended for bus bring-up, when a datasheet's description of
+// the mode is ambiguous. If no mode gives the expected response,
+// DetectMode returns ErrModeNotDetected. In that case, and if an error
+// occurs, the device's original mode is restored if d implements
+// ModeInspector; otherwise it is left in whichever mode was tried last.
+func DetectMode(d Device, probeCmd []byte, expected []byte) (Mode, error) {
+	restore := func() error { return nil }
+	if mi, ok := d.(ModeInspector); ok && Capabilities(d).ModeInspector {
+		orig, err := mi.Mode()
+		if err != nil {
+			return 0, err
+		}
+		restore = func() error { return d.SetMode(orig) }
+	}
+
+	for m := Mode0; m <= Mode3; m++ {
+		if err := d.SetMode(m); err != nil {
+			if errors.Is(err, ErrNotSupported) {
+				continue
+			}
+			restore()
+			return 0, err
+		}
+		ok, err := Probe(d, probeCmd, expected)
+		if err != nil {
+			restore()
+			return 0, err
+		}
+		if ok {
+			return m, nil
+		}
+	}
+	if err := restore(); err != nil {
+		return 0, err
+	}
+	return 0, ErrModeNotDetected
+}
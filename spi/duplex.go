@@ -0,0 +1,85 @@
+package spi
+
+import (
+	"fmt"
+)
+
+// ThreeWireConfigurator is an optional interface implemented by devices
+// that support "3-wire" SPI, where a single bidirectional data line is
+// shared for both directions instead of separate MOSI and MISO lines.
+// On Linux this corresponds to the SPI_3WIRE mode flag.
+//
+// Because data can only travel in one direction at a time in 3-wire mode,
+// full-duplex transfers are meaningless: while it is enabled only
+// half-duplex transfers such as Write, Read and Request are valid, and
+// implementations must return an error if Exchange is called.
+type ThreeWireConfigurator interface {
+	SetThreeWire(enabled bool) error
+}
+
+// DuplexMode describes whether a device's wiring supports transfers in
+// both directions at once.
+type DuplexMode int
+
+const (
+	FullDuplex DuplexMode = 0
+	HalfDuplex DuplexMode = 1
+)
+
+func (m DuplexMode) String() string {
+	switch m {
+	case FullDuplex:
+		return "FullDuplex"
+	case HalfDuplex:
+		return "HalfDuplex"
+	default:
+		return fmt.Sprintf("DuplexMode(%d)", int(m))
+	}
+}
+
+// DuplexConfigurator is an optional interface implemented by devices that
+// allow the caller to declare whether the wiring supports full-duplex
+// transfers, so that mistakes are caught early rather than producing
+// meaningless data.
+//
+// Devices start in FullDuplex mode. In HalfDuplex mode, Exchange returns
+// an error wrapping ErrHalfDuplex while Write, Read and Request continue
+// to work as before. Unlike SetThreeWire, SetDuplex changes only which
+// calls are permitted and not how the bus is driven; enabling 3-wire mode
+// implies half-duplex operation regardless of this setting.
+type DuplexConfigurator interface {
+	SetDuplex(mode DuplexMode) error
+}
+
+// UnidirectionalDevice is an optional interface implemented by devices
+// that can perform transfers which do not drive or sample the data line
+// in the unused direction at all, corresponding to the SPI_TRANS_NO_TX
+// and SPI_TRANS_NO_RX transfer flags in newer Linux kernels. This can
+// reduce bus noise and slightly improve performance.
+//
+// WriteNoRx writes all of data without sampling MISO, and ReadNoTx fills
+// all of buf without driving MOSI. As far as the caller can observe, they
+// are equivalent to a complete Write and a complete Read respectively.
+type UnidirectionalDevice interface {
+	WriteNoRx(data []byte) error
+	ReadNoTx(buf []byte) error
+}
+
+// WriteNoRx writes all of data to the given device, using
+// UnidirectionalDevice if it is implemented, or WriteAll otherwise.
+func WriteNoRx(d Device, data []byte) error {
+	if ud, ok := d.(UnidirectionalDevice); ok {
+		return ud.WriteNoRx(data)
+	}
+	return WriteAll(d, data)
+}
+
+// ReadNoTx fills buf from the given device, using UnidirectionalDevice if
+// it is implemented, or ReadFull otherwise.
+func ReadNoTx(d Device, buf []byte) error {
+	if ud, ok := d.(UnidirectionalDevice); ok {
+		return ud.ReadNoTx(buf)
+	}
+	_, err := ReadFull(d, buf)
+	return err
+}
@@ -0,0 +1,37 @@
+package spi
+
+// Enumerator is implemented by backends that can discover the SPI devices
+// available on the system, such as by listing the spidev nodes on Linux,
+// giving tools a portable way to find devices without hardcoding
+// backend-specific paths.
+type Enumerator interface {
+	// List returns references to all of the devices currently available,
+	// in a stable order defined by the backend.
+	List() ([]DeviceRef, error)
+}
+
+// DeviceRef identifies a device found by an Enumerator, without opening
+// it.
+type DeviceRef interface {
+	// String returns a human-readable identifier for the device, such as
+	// "/dev/spidev0.1", which is suitable for display in a tool.
+	String() string
+
+	// Open opens the device for use. The caller should close the result
+	// with Close once finished with it.
+	Open() (Device, error)
+}
+
+// OpenFirst opens the first device listed by the given enumerator, for
+// simple tools and examples on systems with a single SPI device. It
+// returns ErrNoDevices if the enumerator lists no devices.
+func OpenFirst(e Enumerator) (Device, error) {
+	refs, err := e.List()
+	if err != nil {
+		return nil, err
+	}
+	if len(refs) == 0 {
+		return nil, ErrNoDevices
+	}
+	return refs[0].Open()
+}
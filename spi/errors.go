@@ -0,0 +1,196 @@
+package spi
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrNotSupported is returned by implementations of the optional
+// configuration interfaces when the underlying hardware or driver is not
+// able to honor the requested setting.
+//
+// Implementations may instead return a more specific error, such as
+// ErrModeNotSupported or one created with NotSupported, that wraps
+// ErrNotSupported. Callers detecting lack of support should therefore
+// test for it with errors.Is rather than by comparison.
+var ErrNotSupported = errors.New("not supported by this SPI device")
+
+var (
+	// ErrModeNotSupported is returned by SetMode when the requested mode
+	// cannot be used.
+	ErrModeNotSupported = NotSupported("mode")
+
+	// ErrSpeedNotSupported is returned by SetMaxSpeedHz when no speed at
+	// or below the requested one can be used.
+	ErrSpeedNotSupported = NotSupported("speed")
+)
+
+// NotSupported returns an error reporting that the described operation or
+// setting is not supported, which wraps ErrNotSupported.
+func NotSupported(description string) error {
+	return fmt.Errorf("%s %w", description, ErrNotSupported)
+}
+
+// ErrInvalidMode is returned by SetMode when the given value is not one of
+// the four standard modes. See ValidateMode.
+var ErrInvalidMode = errors.New("invalid SPI mode")
+
+// ErrInvalidSpeed is returned by SetMaxSpeedHz when the given speed is
+// zero. See ValidateSpeed.
+var ErrInvalidSpeed = errors.New("invalid SPI speed")
+
+// ErrBufferLengthMismatch is returned by Exchange when the given outData
+// and inData slices have different lengths.
+var ErrBufferLengthMismatch = errors.New("outData and inData must have the same length")
+
+// ValidateExchange returns ErrBufferLengthMismatch if the given buffers are
+// not valid arguments to Exchange, or nil otherwise. Implementations of
+// Device can call this at the start of Exchange so that misuse is reported
+// consistently across backends.
+func ValidateExchange(outData, inData []byte) error {
+	if len(outData) != len(inData) {
+		return ErrBufferLengthMismatch
+	}
+	return nil
+}
+
+// ErrTimeout is returned by a device created with WithTimeout when a
+// transfer does not complete within the allowed time.
+var ErrTimeout = errors.New("SPI transfer timed out")
+
+// ErrVerifyFailed is returned by WriteVerify when the data read back from
+// the device differs from the data written.
+var ErrVerifyFailed = errors.New("data read back does not match data written")
+
+// ErrPollTimeout is returned by PollBit when the polled bits do not reach
+// the desired state within the allowed time.
+var ErrPollTimeout = errors.New("timed out waiting for status bits")
+
+// ErrInvalidChipSelect is returned when selecting a chip-select line that
+// does not exist on the controller.
+var ErrInvalidChipSelect = errors.New("invalid chip-select index")
+
+// ErrModeNotDetected is returned by DetectMode when the device does not
+// give the expected response in any of the four standard modes.
+var ErrModeNotDetected = errors.New("device did not respond as expected in any mode")
+
+// ErrReplayMismatch is returned by Replay when the device's behavior
+// differs from the behavior that was recorded.
+var ErrReplayMismatch = errors.New("replayed transfer does not match recording")
+
+// ErrNotConfigured is returned by a device created with NewMustConfigure
+// when a transfer is attempted before the device has been configured.
+var ErrNotConfigured = errors.New("SPI device used before being configured")
+
+// ErrCRCMismatch is returned when the CRC received with a frame does not
+// match the frame's contents, typically due to noise on the bus. The
+// transfer can usually be retried.
+var ErrCRCMismatch = errors.New("CRC mismatch")
+
+// ErrShortTransfer is returned by a transfer method that completed fewer
+// bytes than requested. See CheckTransfer.
+var ErrShortTransfer = errors.New("SPI transfer was short")
+
+// CheckTransfer enforces the short-transfer contract of the Device
+// transfer methods on the given results of a call to the named transfer
+// method, such as "Exchange", that was expected to move want bytes: a
+// transfer that completes in full returns a count of want and a nil
+// error, while a short transfer returns the number of bytes completed
+// along with a non-nil error.
+//
+// If err is nil but n is less than want, CheckTransfer returns n and a
+// *TransferError wrapping ErrShortTransfer. If err is not nil, it returns
+// n and err wrapped in a *TransferError, unless err already is one or is
+// io.EOF, which is returned unwrapped as the io.Reader contract requires.
+// Otherwise it returns its arguments unchanged. Backends can use it to
+// wrap their results so that drivers never mistake a short transfer for
+// a successful one:
+//
+//	n, err := d.transfer(outData, inData)
+//	return spi.CheckTransfer("Exchange", n, len(inData), err)
+func CheckTransfer(op string, n, want int, err error) (int, error) {
+	if err == nil && n < want {
+		err = fmt.Errorf("%w: %d of %d bytes", ErrShortTransfer, n, want)
+	}
+	if err == nil || err == io.EOF {
+		return n, err
+	}
+	var te *TransferError
+	if errors.As(err, &te) {
+		return n, err
+	}
+	return n, &TransferError{Op: op, Offset: n, Err: err}
+}
+
+// joinErrors is like errors.Join for two errors, but returns either one
+// unchanged if the other is nil, so that callers can still compare it
+// against a sentinel such as io.EOF.
+func joinErrors(err1, err2 error) error {
+	switch {
+	case err1 == nil:
+		return err2
+	case err2 == nil:
+		return err1
+	default:
+		return errors.Join(err1, err2)
+	}
+}
+
+// writeOnce writes data to w with a single call to Write, for when the
+// data must be framed by one chip-select assertion, reporting a short
+// write as io.ErrShortWrite.
+func writeOnce(w io.Writer, data []byte) error {
+	n, err := w.Write(data)
+	if err == nil && n < len(data) {
+		err = io.ErrShortWrite
+	}
+	return err
+}
+
+// TransferError describes a transfer that failed partway, giving the
+// context that the underlying error alone lacks, so that callers can use
+// errors.As to find how much of the transfer completed for diagnostics
+// or for handling a partial result.
+type TransferError struct {
+	// Op is the name of the transfer method that failed, such as
+	// "Exchange".
+	Op string
+
+	// Offset is the number of bytes that were transferred before the
+	// failure, and so the offset into the buffer at which it occurred.
+	Offset int
+
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *TransferError) Error() string {
+	return fmt.Sprintf("%s failed at byte %d: %s", e.Op, e.Offset, e.Err)
+}
+
+// Unwrap returns the underlying error, so that errors.Is and errors.As can
+// inspect it.
+func (e *TransferError) Unwrap() error {
+	return e.Err
+}
+
+// ErrResponseTimeout is returned by ReadR1 when the card does not begin a
+// response within the allowed number of bytes.
+var ErrResponseTimeout = errors.New("timed out waiting for response token")
+
+// ErrNoDevices is returned by OpenFirst when the enumerator finds no
+// devices.
+var ErrNoDevices = errors.New("no SPI devices found")
+
+// ErrInvalidLaneCount is returned when configuring a number of data lanes
+// other than one, two or four. See ValidateLanes.
+var ErrInvalidLaneCount = errors.New("invalid SPI lane count")
+
+// ErrBusConflict is returned by Bus.Validate when the configurations of
+// devices sharing a bus conflict with each other.
+var ErrBusConflict = errors.New("conflicting configuration on shared bus")
+
+// ErrHalfDuplex is returned by Exchange on a device that has been
+// configured for half-duplex operation.
+var ErrHalfDuplex = errors.New("full-duplex exchange on a half-duplex device")
@@ -0,0 +1,47 @@
+package spi_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+func TestCheckTransfer(t *testing.T) {
+	if _, err := spi.CheckTransfer("Read", 0, 4, io.EOF); err != io.EOF {
+		t.Errorf("io.EOF was not passed through unwrapped: got %#v", err)
+	}
+
+	n, err := spi.CheckTransfer("Exchange", 2, 4, nil)
+	var te *spi.TransferError
+	if !errors.As(err, &te) {
+		t.Fatalf("short transfer returned %#v, want a *TransferError", err)
+	}
+	if n != 2 || te.Op != "Exchange" || te.Offset != 2 || !errors.Is(err, spi.ErrShortTransfer) {
+		t.Errorf("wrong result for short transfer: %d, %s", n, err)
+	}
+}
+
+// shortWriter completes only the first byte of each write.
+type shortWriter struct {
+	*testdevice.Device
+}
+
+func (d shortWriter) Write(data []byte) (int, error) {
+	if len(data) == 0 {
+		return 0, nil
+	}
+	return d.Device.Write(data[:1])
+}
+
+func TestShortWrite(t *testing.T) {
+	d := shortWriter{testdevice.New()}
+	if err := spi.WriteRegister(d, 0x10, []byte{0x01, 0x02}); err != io.ErrShortWrite {
+		t.Errorf("WriteRegister returned %#v, want io.ErrShortWrite", err)
+	}
+	if err := spi.WriteByte(d, 0x01); err != nil {
+		t.Errorf("WriteByte returned unexpected error: %s", err)
+	}
+}
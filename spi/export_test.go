@@ -0,0 +1,4 @@
+package spi
+
+// ReverseBits exposes reverseBits to the external tests.
+var ReverseBits = reverseBits
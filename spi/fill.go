@@ -0,0 +1,15 @@
+package spi
+
+// ReadFillConfigurator is an optional interface implemented by devices that
+// let the caller choose the byte clocked out on MOSI while reading, such
+// as during the read phase of Request or during Read.
+//
+// Without this setting the data sent while reading is undefined, but
+// implementations of this interface send 0x00 until SetReadFillByte is
+// called. Some chips interpret bytes received while they are responding
+// as the start of a new command, and so need MOSI held high (0xFF) or low
+// (0x00) throughout; drivers for such chips should set the fill byte
+// explicitly rather than relying on the default.
+type ReadFillConfigurator interface {
+	SetReadFillByte(b byte) error
+}
@@ -0,0 +1,28 @@
+package spi
+
+import (
+	"fmt"
+)
+
+// ReadFlash performs a read command in the style of 25-series SPI flash and
+// EEPROM chips: it writes the given opcode, followed by the low addrBytes
+// bytes of addr in big-endian order, and then reads readLen bytes, all as
+// a single Request. The bytes read are returned in a newly allocated
+// slice.
+//
+// addrBytes must be between 1 and 4 inclusive, and readLen must not be
+// negative.
+func ReadFlash(d Device, opcode byte, addr uint32, addrBytes int, readLen int) ([]byte, error) {
+	if addrBytes < 1 || addrBytes > 4 {
+		return nil, fmt.Errorf("address width must be between 1 and 4 bytes, not %d", addrBytes)
+	}
+	if readLen < 0 {
+		return nil, fmt.Errorf("invalid read length %d", readLen)
+	}
+	cmd := make([]byte, 1+addrBytes)
+	cmd[0] = opcode
+	for i := 0; i < addrBytes; i++ {
+		cmd[addrBytes-i] = byte(addr >> (8 * uint(i)))
+	}
+	return WriteThenRead(d, cmd, readLen)
+}
@@ -0,0 +1,37 @@
+package spi_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+func TestReadFlash(t *testing.T) {
+	d := testdevice.New()
+	d.Respond([]byte{0xaa, 0xbb})
+	got, err := spi.ReadFlash(d, 0x03, 0x123456, 3, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte{0x03, 0x12, 0x34, 0x56}; !bytes.Equal(d.Written(), want) {
+		t.Errorf("wrote [% x]; want [% x]", d.Written(), want)
+	}
+	if want := []byte{0xaa, 0xbb}; !bytes.Equal(got, want) {
+		t.Errorf("read [% x]; want [% x]", got, want)
+	}
+}
+
+func TestReadFlashInvalid(t *testing.T) {
+	d := testdevice.New()
+	if _, err := spi.ReadFlash(d, 0x03, 0, 3, -1); err == nil {
+		t.Errorf("no error for negative read length")
+	}
+	if _, err := spi.ReadFlash(d, 0x03, 0, 5, 1); err == nil {
+		t.Errorf("no error for five-byte address")
+	}
+	if len(d.Written()) != 0 {
+		t.Errorf("wrote to the device despite invalid arguments")
+	}
+}
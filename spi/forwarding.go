@@ -0,0 +1,320 @@
+package spi
+
+import (
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+// ForwardingDevice is an embeddable base for wrapper types, which
+// forwards the methods of the optional configuration, inspection and
+// lifecycle interfaces in this package to the wrapped device, so that
+// wrapping a capable backend does not hide those capabilities.
+//
+// A wrapper embeds ForwardingDevice in place of Device and overrides the
+// methods whose behavior it changes:
+//
+//	type myWrapper struct {
+//		spi.ForwardingDevice
+//	}
+//
+//	func wrap(d spi.Device) spi.Device {
+//		return myWrapper{spi.ForwardingDevice{Device: d}}
+//	}
+//
+// Since Go cannot add methods at runtime, ForwardingDevice implements all
+// of the interfaces it forwards regardless of the wrapped device, and a
+// forwarded method returns an error wrapping ErrNotSupported if the
+// wrapped device does not implement its interface. The exceptions are
+// Close, Flush and Drain, which do nothing as the package functions of
+// the same names do, and the methods of SpeedRange and TransferLimits,
+// which return zero.
+//
+// A type assertion on a wrapper therefore succeeds for every forwarded
+// interface. Capabilities takes this into account, reporting a forwarded
+// interface only if the wrapped device implements it too, so code that
+// must know whether an interface is really supported should consult it
+// rather than a type assertion alone. For the same reason, a wrapper
+// that implements such an interface itself must not also embed
+// ForwardingDevice.
+//
+// The optional transfer interfaces, such as BatchDevice, are deliberately
+// not forwarded, because transfers made through them would bypass the
+// wrapper's own behavior. A wrapper that can support one correctly must
+// implement it itself, as NewRecordingDevice does for BatchDevice.
+type ForwardingDevice struct {
+	Device
+}
+
+var _ Unwrapper = ForwardingDevice{}
+var _ io.Closer = ForwardingDevice{}
+var _ Flusher = ForwardingDevice{}
+var _ Draining = ForwardingDevice{}
+var _ BitOrderInspector = ForwardingDevice{}
+var _ ChipSelectConfigurator = ForwardingDevice{}
+var _ ChipSelectController = ForwardingDevice{}
+var _ ChipSelectSelector = ForwardingDevice{}
+var _ CSPolarityConfigurator = ForwardingDevice{}
+var _ DeadlineDevice = ForwardingDevice{}
+var _ DelayConfigurator = ForwardingDevice{}
+var _ DuplexConfigurator = ForwardingDevice{}
+var _ Inspector = ForwardingDevice{}
+var _ LaneConfigurator = ForwardingDevice{}
+var _ ModeInspector = ForwardingDevice{}
+var _ RawModeFlags = ForwardingDevice{}
+var _ ReadFillConfigurator = ForwardingDevice{}
+var _ ReadyConfigurator = ForwardingDevice{}
+var _ Resettable = ForwardingDevice{}
+var _ SpeedInspector = ForwardingDevice{}
+var _ SpeedRange = ForwardingDevice{}
+var _ ThreeWireConfigurator = ForwardingDevice{}
+var _ TransferLimits = ForwardingDevice{}
+var _ WordSizeConfigurator = ForwardingDevice{}
+
+// Unwrap returns the wrapped device, implementing Unwrapper.
+func (d ForwardingDevice) Unwrap() Device {
+	return d.Device
+}
+
+// forwardTarget is implemented only by ForwardingDevice, and by types that
+// embed it, so that Capabilities can recognize them.
+func (d ForwardingDevice) forwardTarget() Device {
+	return d.Device
+}
+
+type forwarder interface {
+	forwardTarget() Device
+}
+
+func (d ForwardingDevice) BitOrder() (BitOrder, error) {
+	if bi, ok := d.Device.(BitOrderInspector); ok {
+		return bi.BitOrder()
+	}
+	return 0, NotSupported("bit order inspection")
+}
+
+func (d ForwardingDevice) SetChipSelectActiveHigh(activeHigh bool) error {
+	if c, ok := d.Device.(ChipSelectConfigurator); ok {
+		return c.SetChipSelectActiveHigh(activeHigh)
+	}
+	return NotSupported("chip-select configuration")
+}
+
+func (d ForwardingDevice) SetNoChipSelect(enabled bool) error {
+	if c, ok := d.Device.(ChipSelectConfigurator); ok {
+		return c.SetNoChipSelect(enabled)
+	}
+	return NotSupported("chip-select configuration")
+}
+
+func (d ForwardingDevice) AssertCS() error {
+	if c, ok := d.Device.(ChipSelectController); ok {
+		return c.AssertCS()
+	}
+	return NotSupported("chip-select control")
+}
+
+func (d ForwardingDevice) DeassertCS() error {
+	if c, ok := d.Device.(ChipSelectController); ok {
+		return c.DeassertCS()
+	}
+	return NotSupported("chip-select control")
+}
+
+func (d ForwardingDevice) SetChipSelect(index uint8) error {
+	if s, ok := d.Device.(ChipSelectSelector); ok {
+		return s.SetChipSelect(index)
+	}
+	return NotSupported("chip-select selection")
+}
+
+func (d ForwardingDevice) ChipSelect() (uint8, error) {
+	if s, ok := d.Device.(ChipSelectSelector); ok {
+		return s.ChipSelect()
+	}
+	return 0, NotSupported("chip-select selection")
+}
+
+func (d ForwardingDevice) SetCSPolarity(activeHigh bool) error {
+	if c, ok := d.Device.(CSPolarityConfigurator); ok {
+		return c.SetCSPolarity(activeHigh)
+	}
+	return NotSupported("chip-select polarity")
+}
+
+func (d ForwardingDevice) CSPolarity() (bool, error) {
+	if c, ok := d.Device.(CSPolarityConfigurator); ok {
+		return c.CSPolarity()
+	}
+	return false, NotSupported("chip-select polarity")
+}
+
+func (d ForwardingDevice) SetReadDeadline(t time.Time) error {
+	if dd, ok := d.Device.(DeadlineDevice); ok {
+		return dd.SetReadDeadline(t)
+	}
+	return NotSupported("deadlines")
+}
+
+func (d ForwardingDevice) SetWriteDeadline(t time.Time) error {
+	if dd, ok := d.Device.(DeadlineDevice); ok {
+		return dd.SetWriteDeadline(t)
+	}
+	return NotSupported("deadlines")
+}
+
+func (d ForwardingDevice) SetWordDelay(delay time.Duration) error {
+	if dc, ok := d.Device.(DelayConfigurator); ok {
+		return dc.SetWordDelay(delay)
+	}
+	return NotSupported("word delay")
+}
+
+func (d ForwardingDevice) SetCSChangeDelay(delay time.Duration) error {
+	if dc, ok := d.Device.(DelayConfigurator); ok {
+		return dc.SetCSChangeDelay(delay)
+	}
+	return NotSupported("chip-select change delay")
+}
+
+func (d ForwardingDevice) SetCSSetupDelay(delay time.Duration) error {
+	if dc, ok := d.Device.(DelayConfigurator); ok {
+		return dc.SetCSSetupDelay(delay)
+	}
+	return NotSupported("chip-select setup delay")
+}
+
+func (d ForwardingDevice) SetCSHoldDelay(delay time.Duration) error {
+	if dc, ok := d.Device.(DelayConfigurator); ok {
+		return dc.SetCSHoldDelay(delay)
+	}
+	return NotSupported("chip-select hold delay")
+}
+
+func (d ForwardingDevice) SetDuplex(mode DuplexMode) error {
+	if dc, ok := d.Device.(DuplexConfigurator); ok {
+		return dc.SetDuplex(mode)
+	}
+	return NotSupported("duplex configuration")
+}
+
+func (d ForwardingDevice) Info() (DeviceInfo, error) {
+	if i, ok := d.Device.(Inspector); ok {
+		return i.Info()
+	}
+	return DeviceInfo{}, NotSupported("device information")
+}
+
+func (d ForwardingDevice) SetTxLanes(n uint8) error {
+	if lc, ok := d.Device.(LaneConfigurator); ok {
+		return lc.SetTxLanes(n)
+	}
+	return NotSupported("lane configuration")
+}
+
+func (d ForwardingDevice) SetRxLanes(n uint8) error {
+	if lc, ok := d.Device.(LaneConfigurator); ok {
+		return lc.SetRxLanes(n)
+	}
+	return NotSupported("lane configuration")
+}
+
+func (d ForwardingDevice) Mode() (Mode, error) {
+	if mi, ok := d.Device.(ModeInspector); ok {
+		return mi.Mode()
+	}
+	return 0, NotSupported("mode inspection")
+}
+
+func (d ForwardingDevice) SetModeFlags(flags uint32) error {
+	if rf, ok := d.Device.(RawModeFlags); ok {
+		return rf.SetModeFlags(flags)
+	}
+	return NotSupported("raw mode flags")
+}
+
+func (d ForwardingDevice) ModeFlags() (uint32, error) {
+	if rf, ok := d.Device.(RawModeFlags); ok {
+		return rf.ModeFlags()
+	}
+	return 0, NotSupported("raw mode flags")
+}
+
+func (d ForwardingDevice) SetReadFillByte(b byte) error {
+	if fc, ok := d.Device.(ReadFillConfigurator); ok {
+		return fc.SetReadFillByte(b)
+	}
+	return NotSupported("read fill byte")
+}
+
+func (d ForwardingDevice) SetReadyHandshake(enabled bool) error {
+	if rc, ok := d.Device.(ReadyConfigurator); ok {
+		return rc.SetReadyHandshake(enabled)
+	}
+	return NotSupported("ready handshake")
+}
+
+func (d ForwardingDevice) Reset() error {
+	if r, ok := d.Device.(Resettable); ok {
+		return r.Reset()
+	}
+	return NotSupported("reset")
+}
+
+func (d ForwardingDevice) MaxSpeedHz() (uint32, error) {
+	if si, ok := d.Device.(SpeedInspector); ok {
+		return si.MaxSpeedHz()
+	}
+	return 0, NotSupported("speed inspection")
+}
+
+func (d ForwardingDevice) MinSpeedHz() uint32 {
+	if sr, ok := d.Device.(SpeedRange); ok {
+		return sr.MinSpeedHz()
+	}
+	return 0
+}
+
+func (d ForwardingDevice) MaxSupportedSpeedHz() uint32 {
+	if sr, ok := d.Device.(SpeedRange); ok {
+		return sr.MaxSupportedSpeedHz()
+	}
+	return 0
+}
+
+func (d ForwardingDevice) SetThreeWire(enabled bool) error {
+	if tw, ok := d.Device.(ThreeWireConfigurator); ok {
+		return tw.SetThreeWire(enabled)
+	}
+	return NotSupported("3-wire mode")
+}
+
+func (d ForwardingDevice) MaxTransferBytes() int {
+	return MaxTransferBytes(d.Device)
+}
+
+func (d ForwardingDevice) SetBitsPerWord(bits uint8) error {
+	if wc, ok := d.Device.(WordSizeConfigurator); ok {
+		return wc.SetBitsPerWord(bits)
+	}
+	return NotSupported("word size")
+}
+
+func (d ForwardingDevice) SetWordByteOrder(order binary.ByteOrder) error {
+	if wc, ok := d.Device.(WordSizeConfigurator); ok {
+		return wc.SetWordByteOrder(order)
+	}
+	return NotSupported("word byte order")
+}
+
+func (d ForwardingDevice) Close() error {
+	return Close(d.Device)
+}
+
+func (d ForwardingDevice) Flush() error {
+	return Flush(d.Device)
+}
+
+func (d ForwardingDevice) Drain() error {
+	return Drain(d.Device)
+}
@@ -0,0 +1,67 @@
+package spi_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+// csDevice adds a ChipSelectController implementation to a test device,
+// counting the calls made to it.
+type csDevice struct {
+	*testdevice.Device
+	cs *csCalls
+}
+
+type csCalls struct {
+	asserts, deasserts int
+}
+
+func (d csDevice) AssertCS() error {
+	d.cs.asserts++
+	return nil
+}
+
+func (d csDevice) DeassertCS() error {
+	d.cs.deasserts++
+	return nil
+}
+
+func TestForwardingChipSelectController(t *testing.T) {
+	cs := &csCalls{}
+	d := spi.NewTraced(csDevice{testdevice.New(), cs}, &lastTrace{})
+	if !spi.Capabilities(d).ChipSelectController {
+		t.Fatalf("traced device does not report ChipSelectController")
+	}
+	if err := d.(spi.ChipSelectController).AssertCS(); err != nil {
+		t.Fatal(err)
+	}
+	if cs.asserts != 1 {
+		t.Errorf("AssertCS was not forwarded to the wrapped device")
+	}
+
+	plain := spi.NewTraced(testdevice.New(), &lastTrace{})
+	if spi.Capabilities(plain).ChipSelectController {
+		t.Errorf("traced plain device reports ChipSelectController")
+	}
+	err := plain.(spi.ChipSelectController).AssertCS()
+	if !errors.Is(err, spi.ErrNotSupported) {
+		t.Errorf("AssertCS on traced plain device returned %v; want ErrNotSupported", err)
+	}
+}
+
+func TestForwardingTransaction(t *testing.T) {
+	cs := &csCalls{}
+	d := spi.NewTraced(csDevice{testdevice.New(), cs}, &lastTrace{})
+	tx := spi.Begin(d)
+	tx.Write([]byte{0x01})
+	tx.Write([]byte{0x02})
+	if err := tx.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if cs.asserts != 1 || cs.deasserts != 1 {
+		t.Errorf("got %d asserts and %d deasserts; want one of each", cs.asserts, cs.deasserts)
+	}
+}
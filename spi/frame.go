@@ -0,0 +1,58 @@
+package spi
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// FrameByteOrder is the byte order that Frame uses for multi-byte values.
+// It defaults to big-endian, the most common order for SPI peripherals.
+// Programs that change it should do so during initialization, before any
+// calls to Frame.
+var FrameByteOrder binary.ByteOrder = binary.BigEndian
+
+// Frame assembles a command frame from the given parts in order, encoding
+// multi-byte values using FrameByteOrder. For example, if FrameByteOrder
+// is big-endian then Frame(byte(0x02), uint16(0x1234)) returns
+// []byte{0x02, 0x12, 0x34}.
+//
+// Each part must be a byte, a []byte, a uint16 or a uint32. Frame returns
+// an error if given a part of any other type, including untyped integer
+// constants, which Go converts to int; convert those explicitly to the
+// intended width.
+func Frame(parts ...interface{}) ([]byte, error) {
+	return appendFrame(nil, FrameByteOrder, parts)
+}
+
+// FrameBE is like Frame but always encodes multi-byte values big-endian.
+func FrameBE(parts ...interface{}) ([]byte, error) {
+	return appendFrame(nil, binary.BigEndian, parts)
+}
+
+// FrameLE is like Frame but always encodes multi-byte values
+// little-endian.
+func FrameLE(parts ...interface{}) ([]byte, error) {
+	return appendFrame(nil, binary.LittleEndian, parts)
+}
+
+func appendFrame(buf []byte, order binary.ByteOrder, parts []interface{}) ([]byte, error) {
+	for i, part := range parts {
+		switch v := part.(type) {
+		case byte:
+			buf = append(buf, v)
+		case []byte:
+			buf = append(buf, v...)
+		case uint16:
+			var b [2]byte
+			order.PutUint16(b[:], v)
+			buf = append(buf, b[:]...)
+		case uint32:
+			var b [4]byte
+			order.PutUint32(b[:], v)
+			buf = append(buf, b[:]...)
+		default:
+			return nil, fmt.Errorf("frame part %d has unsupported type %T", i, part)
+		}
+	}
+	return buf, nil
+}
@@ -0,0 +1,345 @@
+package spi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// WriteThenRead writes out to the given device and then reads readLen
+// bytes from it as a single Request, returning the bytes read in a newly
+// allocated slice.
+//
+// On success the returned slice always has length readLen. A negative
+// readLen is an error.
+func WriteThenRead(d Device, out []byte, readLen int) ([]byte, error) {
+	if readLen < 0 {
+		return nil, fmt.Errorf("invalid read length %d", readLen)
+	}
+	in := make([]byte, readLen)
+	_, err := d.Request(out, in)
+	if err != nil {
+		return nil, err
+	}
+	return in, nil
+}
+
+// Probe checks whether the device responds to the given command with the
+// expected bytes, such as when reading a chip's identification register
+// to check that it is present and of the expected type.
+//
+// It writes cmd and then reads len(expected) bytes as a single Request,
+// returning true only if the bytes read exactly match expected. An error
+// is returned only if the transfer itself fails.
+//
+// Responses of up to 16 bytes, which covers typical identification
+// registers, are read into a fixed-size local buffer; only longer ones
+// need a buffer allocated to suit expected.
+func Probe(d Device, cmd []byte, expected []byte) (bool, error) {
+	var buf [probeBufLen]byte
+	var got []byte
+	if len(expected) <= len(buf) {
+		got = buf[:len(expected)]
+	} else {
+		got = make([]byte, len(expected))
+	}
+	if _, err := d.Request(cmd, got); err != nil {
+		return false, err
+	}
+	return bytes.Equal(got, expected), nil
+}
+
+// probeBufLen is the longest response that Probe reads without
+// allocating a buffer sized to it.
+const probeBufLen = 16
+
+// WriteByte writes the single byte b to the device.
+func WriteByte(d WritableDevice, b byte) error {
+	buf := [1]byte{b}
+	return writeOnce(d, buf[:])
+}
+
+// ReadByte reads a single byte from the device.
+func ReadByte(d ReadableDevice) (byte, error) {
+	var buf [1]byte
+	n, err := d.Read(buf[:])
+	if err == nil && n < 1 {
+		err = io.ErrUnexpectedEOF
+	}
+	return buf[0], err
+}
+
+// ExchangeByte writes the single byte out to the device while
+// simultaneously reading a single byte, which it returns.
+func ExchangeByte(d Device, out byte) (byte, error) {
+	var in [1]byte
+	_, err := d.Exchange([]byte{out}, in[:])
+	return in[0], err
+}
+
+// ExchangeAlloc is like Exchange but reads into a newly allocated slice of
+// len(out) bytes, which it returns.
+//
+// This is convenient for one-off transfers, but allocates on every call.
+// Code that transfers repeatedly should use Exchange with a reused buffer
+// instead.
+func ExchangeAlloc(d Device, out []byte) ([]byte, error) {
+	in := make([]byte, len(out))
+	_, err := d.Exchange(out, in)
+	if err != nil {
+		return nil, err
+	}
+	return in, nil
+}
+
+// ExchangeAligned is like ExchangeAlloc, but compensates for chips whose
+// response to each byte written arrives skewWords bytes later during a
+// full-duplex exchange, such as ADCs that return the result of the
+// previous conversion while receiving the next command.
+//
+// It exchanges out followed by skewWords bytes of 0x00, so that the
+// responses to all of out are clocked in, and then discards the first
+// skewWords bytes read. Byte i of the result is therefore the response to
+// byte i of out, and the result always has length len(out). Words are
+// assumed to be one byte each.
+//
+// out must not be empty, and skewWords must be at least zero and less
+// than len(out).
+func ExchangeAligned(d Device, out []byte, skewWords int) ([]byte, error) {
+	if len(out) == 0 {
+		return nil, errors.New("ExchangeAligned requires at least one byte to write")
+	}
+	if skewWords < 0 || skewWords >= len(out) {
+		return nil, fmt.Errorf("skewWords must be between zero and %d", len(out)-1)
+	}
+	padded := make([]byte, len(out)+skewWords)
+	copy(padded, out)
+	in := make([]byte, len(padded))
+	if _, err := d.Exchange(padded, in); err != nil {
+		return nil, err
+	}
+	return in[skewWords:], nil
+}
+
+// RequestWithDummy writes cmd followed by dummyBytes bytes of 0x00 and
+// then reads readLen bytes, as a single Request, returning the bytes read
+// in a newly allocated slice.
+//
+// This is for devices, such as many SAR ADCs, that need some dummy clock
+// cycles between receiving a command and producing the response. Use
+// RequestWithDummyFill for devices that require a different value to be
+// sent during those cycles. Negative lengths are an error.
+func RequestWithDummy(d Device, cmd []byte, dummyBytes int, readLen int) ([]byte, error) {
+	return RequestWithDummyFill(d, cmd, dummyBytes, 0x00, readLen)
+}
+
+// RequestWithDummyFill is like RequestWithDummy but sends the given fill
+// byte, commonly 0xFF, for each dummy byte.
+func RequestWithDummyFill(d Device, cmd []byte, dummyBytes int, fill byte, readLen int) ([]byte, error) {
+	if dummyBytes < 0 || readLen < 0 {
+		return nil, fmt.Errorf("invalid dummy byte count %d or read length %d", dummyBytes, readLen)
+	}
+	out := make([]byte, len(cmd)+dummyBytes)
+	copy(out, cmd)
+	for i := len(cmd); i < len(out); i++ {
+		out[i] = fill
+	}
+	return WriteThenRead(d, out, readLen)
+}
+
+// WriteAll writes all of data to the device, calling Write repeatedly if
+// it reports a short write, in the same way that io.ReadFull reads
+// repeatedly. It returns an error only if a call to Write fails, or if a
+// call makes no progress at all, in which case the error wraps
+// io.ErrShortWrite.
+//
+// This is the recommended way to send bulk data, since Write may
+// legitimately write fewer bytes than requested on some backends.
+func WriteAll(w WritableDevice, data []byte) error {
+	total := 0
+	for total < len(data) {
+		n, err := w.Write(data[total:])
+		total += n
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return fmt.Errorf("wrote %d of %d bytes: %w", total, len(data), io.ErrShortWrite)
+		}
+	}
+	return nil
+}
+
+// ReadFull reads exactly len(buf) bytes from the device into buf, calling
+// Read repeatedly if it reports a short read. It returns the number of
+// bytes read, which is less than len(buf) only if an error is returned.
+//
+// As with io.ReadFull, the error is io.EOF only if no bytes were read
+// before the device reported io.EOF, and io.ErrUnexpectedEOF if it
+// reported io.EOF after some bytes were read. A call to Read that makes no
+// progress without reporting an error is also treated as the end of the
+// available data, returning an error that wraps io.ErrUnexpectedEOF.
+func ReadFull(r ReadableDevice, buf []byte) (int, error) {
+	total := 0
+	for total < len(buf) {
+		n, err := r.Read(buf[total:])
+		total += n
+		if err == io.EOF {
+			if total == 0 {
+				return 0, io.EOF
+			}
+			if total < len(buf) {
+				return total, io.ErrUnexpectedEOF
+			}
+			return total, nil
+		}
+		if err != nil {
+			return total, err
+		}
+		if n == 0 {
+			return total, fmt.Errorf("read %d of %d bytes: %w", total, len(buf), io.ErrUnexpectedEOF)
+		}
+	}
+	return total, nil
+}
+
+// WriteVerify writes data to the device preceded by writeCmd, and then
+// reads it back with a Request using readCmd, returning an error wrapping
+// ErrVerifyFailed that reports the first differing offset if the data
+// read does not match. This is the usual way to check writes to EEPROM
+// and similar chips.
+//
+// It allocates one buffer for the write frame, of len(writeCmd)+len(data)
+// bytes, and one of len(data) bytes for reading the data back.
+func WriteVerify(d Device, writeCmd, readCmd []byte, data []byte) error {
+	frame := make([]byte, 0, len(writeCmd)+len(data))
+	frame = append(frame, writeCmd...)
+	frame = append(frame, data...)
+	if err := writeOnce(d, frame); err != nil {
+		return err
+	}
+
+	got, err := WriteThenRead(d, readCmd, len(data))
+	if err != nil {
+		return err
+	}
+	for i := range data {
+		if got[i] != data[i] {
+			return fmt.Errorf("%w: offset %d was written as %#04x but read as %#04x", ErrVerifyFailed, i, data[i], got[i])
+		}
+	}
+	return nil
+}
+
+// WriteRepeated writes count copies of the byte b to the device, such as
+// when clearing a display or filling a chain of shift registers.
+//
+// Rather than allocating count bytes, it reuses a buffer of at most 4096
+// bytes, or of the device's limit if it implements TransferLimits, so a
+// large count is written as several consecutive writes. A count of zero
+// or less writes nothing.
+func WriteRepeated(w WritableDevice, b byte, count int) error {
+	if count <= 0 {
+		return nil
+	}
+	size := limitChunk(w, defaultChunkSize)
+	if size > count {
+		size = count
+	}
+	buf := bytes.Repeat([]byte{b}, size)
+	for count > 0 {
+		n := count
+		if n > len(buf) {
+			n = len(buf)
+		}
+		if err := WriteAll(w, buf[:n]); err != nil {
+			return err
+		}
+		count -= n
+	}
+	return nil
+}
+
+// ExchangeScratch is like ExchangeByte but uses the caller's scratch buffer
+// instead of allocating, for hot loops performing many single-byte
+// exchanges. scratch must have a length of at least two, one byte for
+// each direction, and its contents are overwritten.
+func ExchangeScratch(d Device, scratch []byte, out byte) (byte, error) {
+	if len(scratch) < 2 {
+		return 0, errors.New("ExchangeScratch requires a scratch buffer of at least two bytes")
+	}
+	scratch[0] = out
+	_, err := d.Exchange(scratch[0:1], scratch[1:2])
+	return scratch[1], err
+}
+
+// CombinedRequester is an optional interface implemented by devices that
+// can perform a command-then-read request as a single transfer descriptor
+// with chip-select held throughout, such as a single spi_ioc_transfer on
+// Linux whose transmit data covers the command and whose receive data
+// covers the response. This can be meaningfully faster than Request on
+// backends that implement Request as two sub-transfers.
+//
+// RequestCombined has the same meaning as Request: it writes cmd, then
+// fills readBuf, returning the number of bytes read into readBuf.
+type CombinedRequester interface {
+	RequestCombined(cmd, readBuf []byte) (int, error)
+}
+
+// RequestCombined performs a request using the device's native
+// CombinedRequester support if available, or using Request otherwise.
+func RequestCombined(d Device, cmd, readBuf []byte) (int, error) {
+	if cr, ok := d.(CombinedRequester); ok {
+		return cr.RequestCombined(cmd, readBuf)
+	}
+	return d.Request(cmd, readBuf)
+}
+
+// SplitRecords splits buf into consecutive records of recordSize bytes
+// each, such as the individual samples of a burst read from an ADC.
+//
+// The records are views into buf rather than copies, so modifying one
+// modifies buf. It returns an error if recordSize is not positive or the
+// length of buf is not a multiple of it.
+func SplitRecords(buf []byte, recordSize int) ([][]byte, error) {
+	if recordSize <= 0 {
+		return nil, fmt.Errorf("record size must be positive")
+	}
+	if len(buf)%recordSize != 0 {
+		return nil, fmt.Errorf("buffer length %d is not a multiple of record size %d", len(buf), recordSize)
+	}
+	records := make([][]byte, len(buf)/recordSize)
+	for i := range records {
+		start := i * recordSize
+		records[i] = buf[start : start+recordSize : start+recordSize]
+	}
+	return records, nil
+}
+
+// ReadRecords writes cmd and then reads count records of recordSize bytes
+// each as a single Request, returning the records as described for
+// SplitRecords. The records are views into a single newly allocated
+// buffer.
+func ReadRecords(d Device, cmd []byte, recordSize, count int) ([][]byte, error) {
+	if recordSize <= 0 || count < 0 {
+		return nil, fmt.Errorf("record size must be positive and count must not be negative")
+	}
+	buf, err := WriteThenRead(d, cmd, recordSize*count)
+	if err != nil {
+		return nil, err
+	}
+	return SplitRecords(buf, recordSize)
+}
+
+// received returns the part of inData that a transfer reporting n bytes
+// filled, tolerating an out-of-range n from a misbehaving device.
+func received(inData []byte, n int) []byte {
+	if n < 0 {
+		n = 0
+	}
+	if n > len(inData) {
+		n = len(inData)
+	}
+	return inData[:n]
+}
@@ -0,0 +1,123 @@
+package spi_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+func TestWriteThenRead(t *testing.T) {
+	d := testdevice.New()
+	d.Respond([]byte{0xaa, 0xbb})
+	got, err := spi.WriteThenRead(d, []byte{0x01}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte{0xaa, 0xbb}; !bytes.Equal(got, want) {
+		t.Errorf("read [% x]; want [% x]", got, want)
+	}
+
+	if _, err := spi.WriteThenRead(d, []byte{0x01}, -1); err == nil {
+		t.Errorf("no error for negative read length")
+	}
+}
+
+func TestRequestWithDummyFill(t *testing.T) {
+	d := testdevice.New()
+	d.Respond([]byte{0xaa})
+	got, err := spi.RequestWithDummyFill(d, []byte{0x01}, 2, 0xff, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte{0x01, 0xff, 0xff}; !bytes.Equal(d.Written(), want) {
+		t.Errorf("wrote [% x]; want [% x]", d.Written(), want)
+	}
+	if want := []byte{0xaa}; !bytes.Equal(got, want) {
+		t.Errorf("read [% x]; want [% x]", got, want)
+	}
+
+	for _, lens := range [][2]int{{-1, 1}, {1, -1}, {-5, 0}} {
+		if _, err := spi.RequestWithDummy(testdevice.New(), []byte{0x01, 0x02}, lens[0], lens[1]); err == nil {
+			t.Errorf("no error for dummy bytes %d and read length %d", lens[0], lens[1])
+		}
+	}
+}
+
+func TestProbe(t *testing.T) {
+	long := bytes.Repeat([]byte{0x5a}, 20)
+	tests := []struct {
+		resp     []byte
+		expected []byte
+		want     bool
+	}{
+		{[]byte{0x12, 0x34}, []byte{0x12, 0x34}, true},
+		{[]byte{0x12, 0x35}, []byte{0x12, 0x34}, false},
+		{long, long, true},
+		{append(bytes.Repeat([]byte{0x5a}, 19), 0x00), long, false},
+	}
+	for _, test := range tests {
+		d := testdevice.New()
+		d.Respond(test.resp)
+		got, err := spi.Probe(d, []byte{0x0f}, test.expected)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("Probe with response [% x] returned %t; want %t", test.resp, got, test.want)
+		}
+		if want := []byte{0x0f}; !bytes.Equal(d.Written(), want) {
+			t.Errorf("wrote [% x]; want [% x]", d.Written(), want)
+		}
+	}
+}
+
+func TestExchangeAligned(t *testing.T) {
+	d := testdevice.New()
+	d.Respond([]byte{0xff, 0x0a, 0x0b})
+	got, err := spi.ExchangeAligned(d, []byte{0x01, 0x02}, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []byte{0x01, 0x02, 0x00}; !bytes.Equal(d.Written(), want) {
+		t.Errorf("wrote [% x]; want [% x]", d.Written(), want)
+	}
+	if want := []byte{0x0a, 0x0b}; !bytes.Equal(got, want) {
+		t.Errorf("read [% x]; want [% x]", got, want)
+	}
+
+	tests := []struct {
+		out  []byte
+		skew int
+	}{
+		{nil, 0},
+		{[]byte{0x01}, 1},
+		{[]byte{0x01}, -1},
+	}
+	for _, test := range tests {
+		if _, err := spi.ExchangeAligned(testdevice.New(), test.out, test.skew); err == nil {
+			t.Errorf("no error for %d bytes with skew %d", len(test.out), test.skew)
+		}
+	}
+}
+
+func TestExchangeScratchAllocs(t *testing.T) {
+	d := &unsafeDevice{}
+	scratch := make([]byte, 2)
+	allocs := testing.AllocsPerRun(100, func() {
+		spi.ExchangeScratch(d, scratch, 0x5a)
+	})
+	if allocs != 0 {
+		t.Errorf("ExchangeScratch made %v allocations per call, want 0", allocs)
+	}
+}
+
+func BenchmarkExchangeScratch(b *testing.B) {
+	d := &unsafeDevice{}
+	scratch := make([]byte, 2)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		spi.ExchangeScratch(d, scratch, byte(i))
+	}
+}
@@ -0,0 +1,63 @@
+package spi
+
+// NewHooked returns a Device that calls before ahead of each transfer on
+// d and after once it completes, for boards that need side effects around
+// transfers, such as toggling a display's data/command line or enabling a
+// level shifter. Configuration calls are passed through without calling
+// either hook.
+//
+// If before returns an error then the transfer is skipped and that error
+// is returned. Otherwise after is always called, even if the transfer
+// fails, and any error it returns is joined with the transfer's error
+// using errors.Join. If after succeeds then the transfer's error is
+// returned unchanged. Either hook may be nil.
+func NewHooked(d Device, before, after func() error) Device {
+	return hooked{
+		ForwardingDevice: ForwardingDevice{Device: d},
+		before:           before,
+		after:            after,
+	}
+}
+
+type hooked struct {
+	ForwardingDevice
+	before, after func() error
+}
+
+func (d hooked) Write(data []byte) (int, error) {
+	return d.hook(func() (int, error) {
+		return d.Device.Write(data)
+	})
+}
+
+func (d hooked) Read(data []byte) (int, error) {
+	return d.hook(func() (int, error) {
+		return d.Device.Read(data)
+	})
+}
+
+func (d hooked) Exchange(outData []byte, inData []byte) (int, error) {
+	return d.hook(func() (int, error) {
+		return d.Device.Exchange(outData, inData)
+	})
+}
+
+func (d hooked) Request(outData []byte, inData []byte) (int, error) {
+	return d.hook(func() (int, error) {
+		return d.Device.Request(outData, inData)
+	})
+}
+
+// hook runs the given transfer between the two hooks.
+func (d hooked) hook(transfer func() (int, error)) (int, error) {
+	if d.before != nil {
+		if err := d.before(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := transfer()
+	if d.after != nil {
+		err = joinErrors(err, d.after())
+	}
+	return n, err
+}
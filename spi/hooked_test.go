@@ -0,0 +1,30 @@
+package spi_test
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+func TestHookedErrors(t *testing.T) {
+	ok := func() error { return nil }
+	d := spi.NewHooked(testdevice.FailAfter(0, io.EOF), ok, ok)
+	if _, err := d.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("Read returned %#v; want io.EOF itself", err)
+	}
+
+	afterErr := errors.New("after failed")
+	d = spi.NewHooked(testdevice.FailAfter(0, io.EOF), nil, func() error { return afterErr })
+	_, err := d.Read(make([]byte, 1))
+	if !errors.Is(err, io.EOF) || !errors.Is(err, afterErr) {
+		t.Errorf("Read returned %v; want both errors", err)
+	}
+
+	d = spi.NewHooked(testdevice.New(), nil, func() error { return afterErr })
+	if _, err := d.Write([]byte{0x01}); err != afterErr {
+		t.Errorf("Write returned %v; want the after error", err)
+	}
+}
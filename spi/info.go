@@ -0,0 +1,26 @@
+package spi
+
+// DeviceInfo describes where an SPI device is attached, for use by
+// diagnostic tools.
+//
+// Implementations fill in whichever fields they know and leave the rest
+// set to their zero values.
+type DeviceInfo struct {
+	// BusNumber identifies the SPI controller the device is attached to.
+	BusNumber int
+
+	// ChipSelect is the index of the chip-select line that selects the
+	// device on its bus.
+	ChipSelect int
+
+	// DriverName is a short name for the backend providing the device,
+	// such as "linuxspi".
+	DriverName string
+}
+
+// Inspector is an optional interface implemented by devices that can
+// describe where they are attached, so that tooling can describe any
+// device without type-asserting to concrete backend types.
+type Inspector interface {
+	Info() (DeviceInfo, error)
+}
@@ -0,0 +1,105 @@
+package spi
+
+import (
+	"io"
+	"time"
+)
+
+// ClosableDevice is a Device that holds resources, such as an open file
+// descriptor or bus handle, that must be released when the device is no
+// longer needed.
+//
+// Code that receives a Device and is responsible for its lifetime should
+// type-assert for io.Closer (or use the Close function) and close it when
+// done. After Close returns, no other methods may be called on the device.
+type ClosableDevice interface {
+	Device
+	io.Closer
+}
+
+// Close closes the given device if it implements io.Closer, returning the
+// result. For any other value it does nothing and returns nil, so that
+// generic code can clean up any device uniformly.
+func Close(d interface{}) error {
+	if c, ok := d.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// Flusher is an optional interface implemented by devices and writers that
+// buffer written data, allowing the caller to force any pending data out
+// to the bus, such as before reading a response.
+//
+// A device or writer that does not buffer has nothing to flush, so
+// callers can use the Flush function to flush any value unconditionally.
+type Flusher interface {
+	Flush() error
+}
+
+// Flush flushes the given value if it implements Flusher, returning the
+// result. For any other value, such as a plain unbuffered Device, it does
+// nothing and returns nil.
+func Flush(d interface{}) error {
+	if f, ok := d.(Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+// Resettable is an optional interface implemented by devices that can
+// recover from a failed transfer that left the controller in a bad state.
+//
+// Reset clears any error state latched by the backend and then reapplies
+// the device's last-known-good configuration: the settings most recently
+// applied successfully through its Configurator methods, such as by a call
+// to Configure. Reset does not change those settings; it only makes the
+// hardware match them again. Retry logic in drivers can call Reset between
+// attempts.
+type Resettable interface {
+	Reset() error
+}
+
+// Draining is an optional interface implemented by devices whose backend
+// may buffer received data that has not yet been read, allowing the caller
+// to discard any such stale data before starting a new transaction, such
+// as after an aborted one.
+//
+// A device with no concept of pending received data has nothing to
+// drain, so callers can use the Drain function to drain any device
+// unconditionally.
+type Draining interface {
+	Drain() error
+}
+
+// Drain drains the given device if it implements Draining, returning the
+// result. For any other value it does nothing and returns nil.
+func Drain(d interface{}) error {
+	if dr, ok := d.(Draining); ok {
+		return dr.Drain()
+	}
+	return nil
+}
+
+// SoftReset sends a chip's software reset command and then waits for the
+// chip to settle, as many chips require after reset before they accept
+// further commands.
+//
+// The command is sent as a single Write, so that chip-select frames it
+// exactly, and a short write is reported as io.ErrShortWrite. Unlike
+// Resettable, which resets the controller, this resets the peripheral
+// chip, and it does not change the controller's settings.
+// If the reset also clears settings within the chip, such as its
+// operating mode, the caller must reconfigure the chip afterwards.
+func SoftReset(d Device, resetCmd []byte, settleTime time.Duration) error {
+	return SoftResetWithClock(d, resetCmd, settleTime, SystemClock)
+}
+
+// SoftResetWithClock is like SoftReset, but waits using the given clock.
+func SoftResetWithClock(d Device, resetCmd []byte, settleTime time.Duration, clock Clock) error {
+	if err := writeOnce(d, resetCmd); err != nil {
+		return err
+	}
+	clock.Sleep(settleTime)
+	return nil
+}
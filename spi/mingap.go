@@ -0,0 +1,78 @@
+package spi
+
+import (
+	"sync"
+	"time"
+)
+
+// NewMinGap returns a Device that ensures at least gap elapses between the
+// end of each transfer on d and the start of the next, sleeping before a
+// transfer if necessary, for chips that require a minimum idle time
+// between transactions.
+//
+// Unlike DelayConfigurator, this works with any backend, but the gap is
+// measured in software and so is only a lower bound. For a Request, the
+// gap applies before the whole transaction rather than between its two
+// phases. The wrapper holds a lock for the duration of each transfer, so
+// concurrent calls are serialized.
+func NewMinGap(d Device, gap time.Duration) Device {
+	return NewMinGapClock(d, gap, SystemClock)
+}
+
+// NewMinGapClock is like NewMinGap, but measures the gap using the given
+// clock.
+func NewMinGapClock(d Device, gap time.Duration, clock Clock) Device {
+	return &minGap{
+		ForwardingDevice: ForwardingDevice{Device: d},
+		gap:              gap,
+		clock:            clock,
+	}
+}
+
+type minGap struct {
+	ForwardingDevice
+	gap   time.Duration
+	clock Clock
+
+	mu      sync.Mutex
+	lastEnd time.Time
+}
+
+func (d *minGap) Write(data []byte) (int, error) {
+	return d.spaced(func() (int, error) {
+		return d.Device.Write(data)
+	})
+}
+
+func (d *minGap) Read(data []byte) (int, error) {
+	return d.spaced(func() (int, error) {
+		return d.Device.Read(data)
+	})
+}
+
+func (d *minGap) Exchange(outData []byte, inData []byte) (int, error) {
+	return d.spaced(func() (int, error) {
+		return d.Device.Exchange(outData, inData)
+	})
+}
+
+func (d *minGap) Request(outData []byte, inData []byte) (int, error) {
+	return d.spaced(func() (int, error) {
+		return d.Device.Request(outData, inData)
+	})
+}
+
+// spaced runs the given transfer once at least the gap has elapsed since
+// the previous one ended.
+func (d *minGap) spaced(transfer func() (int, error)) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.lastEnd.IsZero() {
+		if wait := d.lastEnd.Add(d.gap).Sub(d.clock.Now()); wait > 0 {
+			d.clock.Sleep(wait)
+		}
+	}
+	n, err := transfer()
+	d.lastEnd = d.clock.Now()
+	return n, err
+}
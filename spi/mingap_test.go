@@ -0,0 +1,44 @@
+package spi_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+func TestMinGap(t *testing.T) {
+	clock := testdevice.NewClock(time.Unix(0, 0))
+	td := testdevice.New()
+	d := spi.NewMinGapClock(td, 10*time.Millisecond, clock)
+
+	// The first transfer has no previous one to wait for.
+	if _, err := d.Write([]byte{0x01}); err != nil {
+		t.Fatal(err)
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := d.Write([]byte{0x02})
+		errs <- err
+	}()
+	waitForWaiters(t, clock, 1)
+	clock.Advance(4 * time.Millisecond)
+	if got := len(td.Written()); got != 1 {
+		t.Errorf("second transfer started %s after the first", 4*time.Millisecond)
+	}
+	clock.Advance(6 * time.Millisecond)
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+
+	// Once the gap has already elapsed, transfers do not wait.
+	clock.Advance(20 * time.Millisecond)
+	if _, err := d.Write([]byte{0x03}); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(td.Written()); got != 3 {
+		t.Errorf("device received %d bytes; want 3", got)
+	}
+}
@@ -0,0 +1,67 @@
+package spi
+
+import (
+	"fmt"
+)
+
+// TransferSpec describes a single transaction on a device that supports
+// multiple data lanes, as used by dual and quad SPI flash chips.
+//
+// A transaction has up to four phases, performed in order while the device
+// remains selected: command, address, dummy cycles, and data. Each phase
+// may use a different number of lanes. A lane count of zero is treated as
+// one, the conventional single-bit MOSI/MISO behavior.
+type TransferSpec struct {
+	Command      []byte
+	CommandLanes int
+
+	Address      []byte
+	AddressLanes int
+
+	// DummyCycles is the number of clock cycles to wait between the
+	// address and data phases, during which the data lines are not
+	// driven.
+	DummyCycles int
+
+	// At most one of Out and In may be set, selecting whether the data
+	// phase writes to or reads from the device.
+	Out       []byte
+	In        []byte
+	DataLanes int
+}
+
+// MultiLaneDevice is an optional interface implemented by devices that can
+// transfer data over two or four data lanes at once.
+//
+// Implementations that support only single-lane transfers return
+// ErrNotSupported if any phase of the given spec uses more than one lane.
+type MultiLaneDevice interface {
+	TransferLanes(spec TransferSpec) error
+}
+
+// LaneConfigurator is an optional interface implemented by devices whose
+// default number of data lanes can be configured separately for each
+// direction, corresponding to the SPI_TX_DUAL, SPI_TX_QUAD, SPI_RX_DUAL
+// and SPI_RX_QUAD mode flags on Linux. A QSPI flash driver might use a
+// single transmit lane for commands but four receive lanes for data.
+//
+// The lane count n must be 1, 2 or 4; implementations return an error
+// wrapping ErrInvalidLaneCount for any other value. Backends that
+// support only single-lane transfers return ErrNotSupported for a count
+// greater than one.
+type LaneConfigurator interface {
+	SetTxLanes(n uint8) error
+	SetRxLanes(n uint8) error
+}
+
+// ValidateLanes returns an error wrapping ErrInvalidLaneCount if the given
+// lane count is not 1, 2 or 4, or nil otherwise. Implementations of
+// LaneConfigurator should call this at the start of each method.
+func ValidateLanes(n uint8) error {
+	switch n {
+	case 1, 2, 4:
+		return nil
+	default:
+		return fmt.Errorf("%w: %d", ErrInvalidLaneCount, n)
+	}
+}
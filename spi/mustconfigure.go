@@ -0,0 +1,109 @@
+package spi
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// NewMustConfigure returns a Device that refuses all transfers on d, with
+// an error wrapping ErrNotConfigured, until each of SetMode, SetBitOrder
+// and SetMaxSpeedHz has succeeded at least once, such as by a call to
+// Configure.
+//
+// This turns the mistake of transferring data before configuring the bus,
+// which otherwise produces garbage on the wire using whatever settings
+// the backend happens to have, into a clear error naming the settings
+// that are missing.
+func NewMustConfigure(d Device) Device {
+	return &mustConfigure{ForwardingDevice: ForwardingDevice{Device: d}}
+}
+
+// Bits of mustConfigure.done recording which setters have succeeded.
+const (
+	configuredMode uint32 = 1 << iota
+	configuredBitOrder
+	configuredSpeed
+
+	configuredAll = configuredMode | configuredBitOrder | configuredSpeed
+)
+
+type mustConfigure struct {
+	ForwardingDevice
+
+	mu   sync.Mutex
+	done uint32
+}
+
+func (d *mustConfigure) SetMode(mode Mode) error {
+	return d.configured(configuredMode, d.Device.SetMode(mode))
+}
+
+func (d *mustConfigure) SetBitOrder(order BitOrder) error {
+	return d.configured(configuredBitOrder, d.Device.SetBitOrder(order))
+}
+
+func (d *mustConfigure) SetMaxSpeedHz(speed uint32) error {
+	return d.configured(configuredSpeed, d.Device.SetMaxSpeedHz(speed))
+}
+
+func (d *mustConfigure) Write(data []byte) (int, error) {
+	if err := d.check(); err != nil {
+		return 0, err
+	}
+	return d.Device.Write(data)
+}
+
+func (d *mustConfigure) Read(data []byte) (int, error) {
+	if err := d.check(); err != nil {
+		return 0, err
+	}
+	return d.Device.Read(data)
+}
+
+func (d *mustConfigure) Exchange(outData []byte, inData []byte) (int, error) {
+	if err := d.check(); err != nil {
+		return 0, err
+	}
+	return d.Device.Exchange(outData, inData)
+}
+
+func (d *mustConfigure) Request(outData []byte, inData []byte) (int, error) {
+	if err := d.check(); err != nil {
+		return 0, err
+	}
+	return d.Device.Request(outData, inData)
+}
+
+// configured records that the setter identified by bit has been called, if
+// it succeeded, and then returns its error unchanged.
+func (d *mustConfigure) configured(bit uint32, err error) error {
+	if err == nil {
+		d.mu.Lock()
+		d.done |= bit
+		d.mu.Unlock()
+	}
+	return err
+}
+
+// check returns an error naming the setters that have not yet succeeded,
+// or nil if all of them have.
+func (d *mustConfigure) check() error {
+	d.mu.Lock()
+	done := d.done
+	d.mu.Unlock()
+	if done == configuredAll {
+		return nil
+	}
+	var missing []string
+	if done&configuredMode == 0 {
+		missing = append(missing, "SetMode")
+	}
+	if done&configuredBitOrder == 0 {
+		missing = append(missing, "SetBitOrder")
+	}
+	if done&configuredSpeed == 0 {
+		missing = append(missing, "SetMaxSpeedHz")
+	}
+	return fmt.Errorf("%w: %s not called", ErrNotConfigured, strings.Join(missing, ", "))
+}
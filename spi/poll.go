@@ -0,0 +1,41 @@
+package spi
+
+import (
+	"time"
+)
+
+// PollBit repeatedly reads a status register until the bits selected by
+// bitmask are all set (if wantSet is true) or all clear (if wantSet is
+// false), such as when waiting for the write-in-progress bit of a flash
+// chip to clear.
+//
+// Each read writes statusCmd and then reads a single status byte, as a
+// single Request. Reads are made interval apart until timeout has
+// elapsed, after which PollBit returns ErrPollTimeout. Errors from the
+// device are returned immediately.
+func PollBit(d Device, statusCmd []byte, bitmask byte, wantSet bool, timeout time.Duration, interval time.Duration) error {
+	return PollBitWithClock(d, statusCmd, bitmask, wantSet, timeout, interval, SystemClock)
+}
+
+// PollBitWithClock is like PollBit, but measures the timeout and waits
+// between reads using the given clock.
+func PollBitWithClock(d Device, statusCmd []byte, bitmask byte, wantSet bool, timeout time.Duration, interval time.Duration, clock Clock) error {
+	want := byte(0)
+	if wantSet {
+		want = bitmask
+	}
+	deadline := clock.Now().Add(timeout)
+	var status [1]byte
+	for {
+		if _, err := d.Request(statusCmd, status[:]); err != nil {
+			return err
+		}
+		if status[0]&bitmask == want {
+			return nil
+		}
+		if !clock.Now().Before(deadline) {
+			return ErrPollTimeout
+		}
+		clock.Sleep(interval)
+	}
+}
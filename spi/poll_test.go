@@ -0,0 +1,47 @@
+package spi_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+func TestPollBit(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []byte
+		mask      byte
+		wantSet   bool
+		sleeps    int
+		want      error
+	}{
+		{"clears", []byte{0x03, 0x01, 0x02}, 0x01, false, 2, nil},
+		{"sets", []byte{0x00, 0x81}, 0x81, true, 1, nil},
+		{"times out", nil, 0x80, true, 3, spi.ErrPollTimeout},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			clock := testdevice.NewClock(time.Unix(0, 0))
+			d := testdevice.New()
+			d.Respond(test.responses)
+
+			errs := make(chan error, 1)
+			go func() {
+				errs <- spi.PollBitWithClock(d, []byte{0x05}, test.mask, test.wantSet, 250*time.Millisecond, 100*time.Millisecond, clock)
+			}()
+			for i := 0; i < test.sleeps; i++ {
+				waitForWaiters(t, clock, 1)
+				clock.Advance(100 * time.Millisecond)
+			}
+			if err := <-errs; err != test.want {
+				t.Errorf("PollBit returned %v; want %v", err, test.want)
+			}
+			if want := bytes.Repeat([]byte{0x05}, test.sleeps+1); !bytes.Equal(d.Written(), want) {
+				t.Errorf("wrote [% x]; want [% x]", d.Written(), want)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package spi
+
+import (
+	"sync"
+	"time"
+)
+
+// NewRateLimited returns a Device that limits the throughput of transfers
+// on d to an average of maxBytesPerSec bytes per second, using a token
+// bucket that allows bursts of up to burst bytes, so that a bulk transfer
+// cannot starve other latency-sensitive devices sharing the controller.
+//
+// Each transfer waits until the bucket has enough tokens for its length,
+// counting both the bytes written and the bytes read by a Request. A
+// transfer longer than burst is allowed, but waits for the bucket to
+// refill by the excess first. If burst is zero or negative, it is set to
+// maxBytesPerSec, allowing up to one second's worth of data at once.
+//
+// The returned device is safe for concurrent use if d is, with concurrent
+// transfers sharing the same bucket. NewRateLimited panics if
+// maxBytesPerSec is not positive.
+func NewRateLimited(d Device, maxBytesPerSec int, burst int) Device {
+	return NewRateLimitedClock(d, maxBytesPerSec, burst, SystemClock)
+}
+
+// NewRateLimitedClock is like NewRateLimited, but measures the refilling of
+// the bucket and waits using the given clock.
+func NewRateLimitedClock(d Device, maxBytesPerSec int, burst int, clock Clock) Device {
+	if maxBytesPerSec <= 0 {
+		panic("NewRateLimited requires a positive maxBytesPerSec")
+	}
+	if burst <= 0 {
+		burst = maxBytesPerSec
+	}
+	return &rateLimited{
+		ForwardingDevice: ForwardingDevice{Device: d},
+		rate:             float64(maxBytesPerSec),
+		burst:            float64(burst),
+		clock:            clock,
+		tokens:           float64(burst),
+		last:             clock.Now(),
+	}
+}
+
+type rateLimited struct {
+	ForwardingDevice
+	rate  float64 // tokens added per second
+	burst float64
+	clock Clock
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func (d *rateLimited) Write(data []byte) (int, error) {
+	d.wait(len(data))
+	return d.Device.Write(data)
+}
+
+func (d *rateLimited) Read(data []byte) (int, error) {
+	d.wait(len(data))
+	return d.Device.Read(data)
+}
+
+func (d *rateLimited) Exchange(outData []byte, inData []byte) (int, error) {
+	d.wait(len(outData))
+	return d.Device.Exchange(outData, inData)
+}
+
+func (d *rateLimited) Request(outData []byte, inData []byte) (int, error) {
+	d.wait(len(outData) + len(inData))
+	return d.Device.Request(outData, inData)
+}
+
+// wait takes the given number of tokens from the bucket, first waiting for
+// it to refill if there are too few.
+//
+// Tokens are reserved before waiting, leaving the bucket in debt, so that
+// concurrent callers queue behind each other rather than all waking at
+// once to compete for the same tokens.
+func (d *rateLimited) wait(byteCount int) {
+	d.mu.Lock()
+	now := d.clock.Now()
+	d.tokens += now.Sub(d.last).Seconds() * d.rate
+	if d.tokens > d.burst {
+		d.tokens = d.burst
+	}
+	d.last = now
+	d.tokens -= float64(byteCount)
+	deficit := -d.tokens
+	d.mu.Unlock()
+
+	if deficit > 0 {
+		d.clock.Sleep(time.Duration(deficit / d.rate * float64(time.Second)))
+	}
+}
@@ -0,0 +1,59 @@
+package spi_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+func TestRateLimited(t *testing.T) {
+	clock := testdevice.NewClock(time.Unix(0, 0))
+	d := spi.NewRateLimitedClock(testdevice.New(), 100, 10, clock)
+
+	// A full burst is allowed immediately.
+	if _, err := d.Write(make([]byte, 10)); err != nil {
+		t.Fatal(err)
+	}
+
+	errs := make(chan error, 1)
+	wait := func(transfer func() error, want time.Duration) {
+		t.Helper()
+		go func() {
+			errs <- transfer()
+		}()
+		waitForWaiters(t, clock, 1)
+		clock.Advance(want - time.Millisecond)
+		if got := clock.Waiters(); got != 1 {
+			t.Fatalf("transfer waited less than %s", want)
+		}
+		clock.Advance(time.Millisecond)
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The bucket is empty, so five bytes need 50ms to refill.
+	wait(func() error {
+		_, err := d.Write(make([]byte, 5))
+		return err
+	}, 50*time.Millisecond)
+
+	// The bucket refills only up to the burst size, so a request for 15
+	// bytes in total must wait for the remaining five.
+	clock.Advance(time.Second)
+	wait(func() error {
+		_, err := d.Request(make([]byte, 5), make([]byte, 10))
+		return err
+	}, 50*time.Millisecond)
+}
+
+func TestRateLimitedInvalid(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("no panic for a zero rate")
+		}
+	}()
+	spi.NewRateLimited(testdevice.New(), 0, 10)
+}
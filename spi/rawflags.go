@@ -0,0 +1,52 @@
+package spi
+
+// RawModeFlags is an optional interface implemented by devices that expose
+// their backend's native word of mode flags, as an escape hatch for
+// settings that the rest of this package does not model.
+//
+// The meaning of each bit is entirely implementation-specific, so code
+// using this interface is not portable between backends. Backends built
+// on Linux spidev use the kernel's SPI_* mode bits, for which the
+// LinuxFlag constants are provided.
+type RawModeFlags interface {
+	// SetModeFlags replaces the device's entire flag word.
+	SetModeFlags(flags uint32) error
+
+	// ModeFlags returns the device's current flag word.
+	ModeFlags() (uint32, error)
+}
+
+// Mode flag bits used by Linux spidev, as defined in linux/spi/spi.h, for
+// use with RawModeFlags on backends that use the Linux flag layout.
+const (
+	LinuxFlagCPHA     uint32 = 0x0001
+	LinuxFlagCPOL     uint32 = 0x0002
+	LinuxFlagCSHigh   uint32 = 0x0004
+	LinuxFlagLSBFirst uint32 = 0x0008
+	LinuxFlag3Wire    uint32 = 0x0010
+	LinuxFlagLoop     uint32 = 0x0020
+	LinuxFlagNoCS     uint32 = 0x0040
+	LinuxFlagReady    uint32 = 0x0080
+	LinuxFlagTxDual   uint32 = 0x0100
+	LinuxFlagTxQuad   uint32 = 0x0200
+	LinuxFlagRxDual   uint32 = 0x0400
+	LinuxFlagRxQuad   uint32 = 0x0800
+	LinuxFlagCSWord   uint32 = 0x1000
+	LinuxFlagTxOctal  uint32 = 0x2000
+	LinuxFlagRxOctal  uint32 = 0x4000
+	LinuxFlag3WireHiZ uint32 = 0x8000
+)
+
+// ReadyConfigurator is an optional interface implemented by devices whose
+// controller can wait for a slow peripheral to signal readiness on a
+// separate READY line, corresponding to the SPI_READY mode flag
+// (LinuxFlagReady) on Linux.
+//
+// When the handshake is enabled, the controller pauses between words
+// until the peripheral asserts READY. Enabling it on hardware with no
+// READY line connected may therefore hang the bus indefinitely.
+// Implementations that cannot perform the handshake return
+// ErrNotSupported.
+type ReadyConfigurator interface {
+	SetReadyHandshake(enabled bool) error
+}
@@ -0,0 +1,300 @@
+package spi
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// TransferRecord describes a single call made through a RecordingDevice.
+type TransferRecord struct {
+	// Op is the name of the method that was called, such as "Exchange"
+	// or "SetMode".
+	Op string
+
+	// Arg is the argument given to a configuration method: a Mode for
+	// SetMode, a BitOrder for SetBitOrder and a uint32 for SetMaxSpeedHz.
+	// It is nil for transfers.
+	Arg interface{}
+
+	// Out and In are copies of the data written to and read from the
+	// device, respectively. Either may be nil if the operation does not
+	// transfer data in that direction.
+	Out []byte
+	In  []byte
+
+	// N and Err are the results returned by a transfer method. Err is
+	// also set for configuration methods that failed.
+	N   int
+	Err error
+}
+
+// String returns a single-line, human-readable description of the record.
+func (r TransferRecord) String() string {
+	var buf strings.Builder
+	buf.WriteString(r.Op)
+	if r.Arg != nil {
+		fmt.Fprintf(&buf, " %v", r.Arg)
+	}
+	if r.Out != nil {
+		fmt.Fprintf(&buf, " out=[% x]", r.Out)
+	}
+	if r.In != nil {
+		fmt.Fprintf(&buf, " in=[% x]", r.In)
+	}
+	if r.Arg == nil {
+		fmt.Fprintf(&buf, " n=%d", r.N)
+	}
+	if r.Err != nil {
+		fmt.Fprintf(&buf, " err=%q", r.Err.Error())
+	}
+	return buf.String()
+}
+
+// RecordingDevice is a Device that passes all calls through to another
+// device while recording each call, including configuration calls, so that
+// a full session on the bus can be reconstructed.
+//
+// A RecordingDevice is safe for concurrent use if the wrapped device is.
+type RecordingDevice interface {
+	Device
+	Unwrapper
+
+	// Records returns the calls recorded so far, in the order they were
+	// made. It always returns an empty result if the device was created
+	// with a log writer.
+	Records() []TransferRecord
+}
+
+// NewRecordingDevice returns a RecordingDevice wrapping the given device.
+//
+// If log is non-nil then each call is written to it as a single line of
+// text as it happens. Otherwise, calls are accumulated in memory and can
+// be retrieved with Records.
+//
+// If d implements BatchDevice then so does the result, recording each
+// segment of a batch as a Write, Read or Exchange once the whole batch
+// has completed.
+func NewRecordingDevice(d Device, log io.Writer) RecordingDevice {
+	r := &recording{
+		ForwardingDevice: ForwardingDevice{Device: d},
+		log:              log,
+	}
+	if bd, ok := d.(BatchDevice); ok {
+		return batchRecording{recording: r, batch: bd}
+	}
+	return r
+}
+
+type recording struct {
+	ForwardingDevice
+	log io.Writer
+
+	mu      sync.Mutex
+	records []TransferRecord
+}
+
+// batchRecording is a recording of a device that also implements
+// BatchDevice.
+type batchRecording struct {
+	*recording
+	batch BatchDevice
+}
+
+var _ RecordingDevice = (*recording)(nil)
+var _ RecordingDevice = batchRecording{}
+var _ BatchDevice = batchRecording{}
+
+func (d batchRecording) Transfer(segments []Segment) error {
+	err := d.batch.Transfer(segments)
+	for _, seg := range segments {
+		r := TransferRecord{Out: copyBytes(seg.Out), In: copyBytes(seg.In), Err: err}
+		switch {
+		case seg.Out != nil && seg.In != nil:
+			r.Op, r.N = "Exchange", len(seg.In)
+		case seg.Out != nil:
+			r.Op, r.N = "Write", len(seg.Out)
+		default:
+			r.Op, r.N = "Read", len(seg.In)
+		}
+		if err != nil {
+			// The batch reports only a single error, so we cannot
+			// know how much of any segment was transferred.
+			r.N = 0
+			r.In = copyBytes(received(seg.In, 0))
+		}
+		d.record(r)
+	}
+	return err
+}
+
+func (d *recording) Records() []TransferRecord {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]TransferRecord(nil), d.records...)
+}
+
+func (d *recording) SetMode(mode Mode) error {
+	err := d.Device.SetMode(mode)
+	d.record(TransferRecord{Op: "SetMode", Arg: mode, Err: err})
+	return err
+}
+
+func (d *recording) SetBitOrder(order BitOrder) error {
+	err := d.Device.SetBitOrder(order)
+	d.record(TransferRecord{Op: "SetBitOrder", Arg: order, Err: err})
+	return err
+}
+
+func (d *recording) SetMaxSpeedHz(speed uint32) error {
+	err := d.Device.SetMaxSpeedHz(speed)
+	d.record(TransferRecord{Op: "SetMaxSpeedHz", Arg: speed, Err: err})
+	return err
+}
+
+func (d *recording) Write(data []byte) (int, error) {
+	n, err := d.Device.Write(data)
+	d.record(TransferRecord{Op: "Write", Out: copyBytes(data), N: n, Err: err})
+	return n, err
+}
+
+func (d *recording) Read(data []byte) (int, error) {
+	n, err := d.Device.Read(data)
+	d.record(TransferRecord{Op: "Read", In: copyBytes(received(data, n)), N: n, Err: err})
+	return n, err
+}
+
+func (d *recording) Exchange(outData []byte, inData []byte) (int, error) {
+	n, err := d.Device.Exchange(outData, inData)
+	d.record(TransferRecord{Op: "Exchange", Out: copyBytes(outData), In: copyBytes(received(inData, n)), N: n, Err: err})
+	return n, err
+}
+
+func (d *recording) Request(outData []byte, inData []byte) (int, error) {
+	n, err := d.Device.Request(outData, inData)
+	d.record(TransferRecord{Op: "Request", Out: copyBytes(outData), In: copyBytes(received(inData, n)), N: n, Err: err})
+	return n, err
+}
+
+func (d *recording) record(r TransferRecord) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.log != nil {
+		fmt.Fprintln(d.log, r.String())
+		return
+	}
+	d.records = append(d.records, r)
+}
+
+func copyBytes(buf []byte) []byte {
+	if buf == nil {
+		return nil
+	}
+	return append(make([]byte, 0, len(buf)), buf...)
+}
+
+// ReplayOptions customizes the checks made by ReplayWithOptions.
+type ReplayOptions struct {
+	// CheckReads enables checking that the data read during each
+	// replayed transfer matches the data recorded.
+	CheckReads bool
+
+	// ReadTolerance is the largest difference allowed between each
+	// recorded byte and the corresponding replayed byte when CheckReads
+	// is enabled, for data such as analog samples that are not expected
+	// to reproduce exactly.
+	ReadTolerance byte
+}
+
+// Replay reissues a sequence of calls recorded by a RecordingDevice
+// against the given device, which need not be the device the calls were
+// originally made on, to reproduce a captured session on a test rig.
+//
+// It is equivalent to ReplayWithOptions with the zero ReplayOptions, which
+// checks only that each transfer is accepted in full.
+func Replay(d Device, log []TransferRecord) error {
+	return ReplayWithOptions(d, log, ReplayOptions{})
+}
+
+// ReplayWithOptions reissues each of the given records in order, writing
+// the recorded data and reading into buffers of the recorded sizes. It
+// stops at the first record that fails, or that does not write and read
+// the same number of bytes as was recorded, returning an error. Such a
+// mismatch is reported with an error wrapping ErrReplayMismatch that
+// identifies the record by its index in log.
+//
+// Records that failed when they were recorded are reissued like any
+// other, but are expected to fail again; their errors are not compared.
+func ReplayWithOptions(d Device, log []TransferRecord, opts ReplayOptions) error {
+	for i, r := range log {
+		got, err := replayRecord(d, r)
+		if r.Err != nil {
+			// We only care that the real sequence of calls is
+			// reproduced, not how the failure manifests.
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("record %d (%s): %w", i, r.Op, err)
+		}
+		if got.N != r.N {
+			return fmt.Errorf("%w: record %d (%s) transferred %d bytes, but %d were recorded", ErrReplayMismatch, i, r.Op, got.N, r.N)
+		}
+		if opts.CheckReads {
+			for j := range r.In {
+				if byteDiff(got.In[j], r.In[j]) > opts.ReadTolerance {
+					return fmt.Errorf("%w: record %d (%s) read %#04x at offset %d, but %#04x was recorded", ErrReplayMismatch, i, r.Op, got.In[j], j, r.In[j])
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// replayRecord reissues the call described by a single record, returning a
+// record of the new results.
+func replayRecord(d Device, r TransferRecord) (TransferRecord, error) {
+	got := TransferRecord{Op: r.Op, Arg: r.Arg}
+	if r.In != nil {
+		got.In = make([]byte, len(r.In))
+	}
+	var err error
+	switch r.Op {
+	case "SetMode":
+		mode, ok := r.Arg.(Mode)
+		if !ok {
+			return got, fmt.Errorf("invalid argument %#v", r.Arg)
+		}
+		err = d.SetMode(mode)
+	case "SetBitOrder":
+		order, ok := r.Arg.(BitOrder)
+		if !ok {
+			return got, fmt.Errorf("invalid argument %#v", r.Arg)
+		}
+		err = d.SetBitOrder(order)
+	case "SetMaxSpeedHz":
+		speed, ok := r.Arg.(uint32)
+		if !ok {
+			return got, fmt.Errorf("invalid argument %#v", r.Arg)
+		}
+		err = d.SetMaxSpeedHz(speed)
+	case "Write":
+		got.N, err = d.Write(r.Out)
+	case "Read":
+		got.N, err = d.Read(got.In)
+	case "Exchange":
+		got.N, err = d.Exchange(r.Out, got.In)
+	case "Request":
+		got.N, err = d.Request(r.Out, got.In)
+	default:
+		return got, fmt.Errorf("unsupported operation %q", r.Op)
+	}
+	return got, err
+}
+
+func byteDiff(a, b byte) byte {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
@@ -0,0 +1,101 @@
+package spi_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+// batchDevice adds a BatchDevice implementation to a test device by
+// performing each segment as a separate call.
+type batchDevice struct {
+	*testdevice.Device
+}
+
+func (d batchDevice) Transfer(segments []spi.Segment) error {
+	for _, seg := range segments {
+		var err error
+		switch {
+		case seg.Out != nil && seg.In != nil:
+			_, err = d.Exchange(seg.Out, seg.In)
+		case seg.Out != nil:
+			_, err = d.Write(seg.Out)
+		default:
+			_, err = d.Read(seg.In)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestRecordingDeviceCapabilities(t *testing.T) {
+	batch := spi.NewRecordingDevice(batchDevice{testdevice.New()}, nil)
+	if !spi.Capabilities(batch).BatchDevice {
+		t.Errorf("wrapping a BatchDevice does not report batch support")
+	}
+
+	plain := spi.NewRecordingDevice(testdevice.New(), nil)
+	if spi.Capabilities(plain).BatchDevice {
+		t.Errorf("wrapping a plain device reports batch support")
+	}
+	if spi.Capabilities(plain).ChipSelectController {
+		t.Errorf("wrapping a plain device reports chip-select control")
+	}
+}
+
+func TestRecordingDeviceTransfer(t *testing.T) {
+	td := testdevice.New()
+	td.Respond([]byte{0xaa, 0xbb})
+	d := spi.NewRecordingDevice(batchDevice{td}, nil)
+
+	in := make([]byte, 2)
+	err := d.(spi.BatchDevice).Transfer([]spi.Segment{
+		{Out: []byte{0x01}, KeepCS: true},
+		{In: in},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records := d.Records()
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	if got := records[0]; got.Op != "Write" || !bytes.Equal(got.Out, []byte{0x01}) || got.N != 1 {
+		t.Errorf("wrong first record: %s", got)
+	}
+	if got := records[1]; got.Op != "Read" || !bytes.Equal(got.In, []byte{0xaa, 0xbb}) || got.N != 2 {
+		t.Errorf("wrong second record: %s", got)
+	}
+}
+
+// shortDevice completes only the first byte of each exchange.
+type shortDevice struct {
+	*testdevice.Device
+}
+
+func (d shortDevice) Exchange(outData []byte, inData []byte) (int, error) {
+	if len(inData) == 0 {
+		return 0, nil
+	}
+	return d.Device.Exchange(outData[:1], inData[:1])
+}
+
+func TestRecordingDeviceShortExchange(t *testing.T) {
+	td := testdevice.New()
+	td.Respond([]byte{0xaa, 0xbb})
+	d := spi.NewRecordingDevice(shortDevice{td}, nil)
+
+	d.Exchange([]byte{0x01, 0x02}, make([]byte, 2))
+	records := d.Records()
+	if len(records) != 1 {
+		t.Fatalf("got %d records, want 1", len(records))
+	}
+	if got := records[0]; got.N != 1 || !bytes.Equal(got.In, []byte{0xaa}) {
+		t.Errorf("wrong record for short exchange: %s", got)
+	}
+}
@@ -0,0 +1,132 @@
+package spi
+
+import (
+	"fmt"
+)
+
+// RegisterConfig describes how a particular chip expects register reads
+// and writes to be framed.
+//
+// Many SPI chips expose a register file where each transaction begins
+// with a command byte containing the register address along with a single
+// bit that distinguishes reads from writes. A read is then followed by
+// the register value being clocked in, while a write is followed by the
+// new value being clocked out.
+type RegisterConfig struct {
+	// RWBit is the position of the read/write bit within the command
+	// byte, from 0 for the least significant bit through 7 for the most
+	// significant bit.
+	RWBit uint
+
+	// ReadBitClear inverts the read/write bit polarity. By default the
+	// bit is set for reads and cleared for writes; if ReadBitClear is
+	// true then the bit is instead cleared for reads and set for writes.
+	ReadBitClear bool
+
+	// AutoIncrement indicates that the chip advances to the next register
+	// address after each byte when several bytes are read in a single
+	// transaction, allowing consecutive registers to be read at once.
+	// If false, each register must be addressed separately.
+	AutoIncrement bool
+
+	// AutoIncrementMask is set in the command byte of multi-register
+	// reads when AutoIncrement is true, for chips that auto-increment
+	// only when requested by a particular command bit. It is zero for
+	// chips that always auto-increment.
+	AutoIncrementMask byte
+}
+
+// DefaultRegisterConfig is the most common register command convention,
+// where the most significant bit of the command byte is set for reads.
+var DefaultRegisterConfig = RegisterConfig{
+	RWBit: 7,
+}
+
+// ReadCommand returns the command byte that begins a read of the register
+// at the given address.
+func (c RegisterConfig) ReadCommand(addr byte) byte {
+	return c.command(addr, !c.ReadBitClear)
+}
+
+// WriteCommand returns the command byte that begins a write to the
+// register at the given address.
+func (c RegisterConfig) WriteCommand(addr byte) byte {
+	return c.command(addr, c.ReadBitClear)
+}
+
+// DecodeCommand is the inverse of ReadCommand and WriteCommand, returning
+// the register address and whether the given command byte begins a read.
+// Any AutoIncrementMask bits are excluded from the returned address.
+func (c RegisterConfig) DecodeCommand(cmd byte) (addr byte, read bool) {
+	bit := byte(1) << c.RWBit
+	set := cmd&bit != 0
+	return cmd &^ bit &^ c.AutoIncrementMask, set != c.ReadBitClear
+}
+
+func (c RegisterConfig) command(addr byte, set bool) byte {
+	bit := byte(1) << c.RWBit
+	if set {
+		return addr | bit
+	}
+	return addr &^ bit
+}
+
+// ReadRegister reads len(buf) bytes from the register at the given address
+// into buf, using a single Request.
+func (c RegisterConfig) ReadRegister(d Device, addr byte, buf []byte) error {
+	_, err := d.Request([]byte{c.ReadCommand(addr)}, buf)
+	return err
+}
+
+// WriteRegister writes the given data to the register at the given
+// address, sending the command byte and data as a single transfer.
+func (c RegisterConfig) WriteRegister(d Device, addr byte, data []byte) error {
+	frame := make([]byte, 0, len(data)+1)
+	frame = append(frame, c.WriteCommand(addr))
+	frame = append(frame, data...)
+	return writeOnce(d, frame)
+}
+
+// ReadRegister reads from a register using DefaultRegisterConfig.
+func ReadRegister(d Device, addr byte, buf []byte) error {
+	return DefaultRegisterConfig.ReadRegister(d, addr, buf)
+}
+
+// WriteRegister writes to a register using DefaultRegisterConfig.
+func WriteRegister(d Device, addr byte, data []byte) error {
+	return DefaultRegisterConfig.WriteRegister(d, addr, data)
+}
+
+// DumpRegisters reads count consecutive registers starting at startAddr,
+// returning their values in address order.
+//
+// If cfg.AutoIncrement is set then all of the registers are read in a
+// single transaction. Otherwise each register is read with its own
+// transaction.
+//
+// DumpRegisters returns an error without reading anything if count is
+// negative or if the range of registers would extend past address 0xff.
+func DumpRegisters(d Device, startAddr byte, count int, cfg RegisterConfig) ([]byte, error) {
+	if count < 0 {
+		return nil, fmt.Errorf("invalid register count %d", count)
+	}
+	if int(startAddr)+count > 256 {
+		return nil, fmt.Errorf("%d registers starting at %#04x would wrap past address 0xff", count, startAddr)
+	}
+	buf := make([]byte, count)
+	if cfg.AutoIncrement {
+		cmd := cfg.ReadCommand(startAddr) | cfg.AutoIncrementMask
+		if _, err := d.Request([]byte{cmd}, buf); err != nil {
+			return nil, err
+		}
+		return buf, nil
+	}
+
+	for i := range buf {
+		addr := startAddr + byte(i)
+		if err := cfg.ReadRegister(d, addr, buf[i:i+1]); err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
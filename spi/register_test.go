@@ -0,0 +1,34 @@
+package spi_test
+
+import (
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+func TestDumpRegistersInvalidRange(t *testing.T) {
+	tests := []struct {
+		start byte
+		count int
+	}{
+		{0x00, -1},
+		{0xff, 2},
+		{0x80, 129},
+	}
+	for _, test := range tests {
+		d := testdevice.New()
+		if _, err := spi.DumpRegisters(d, test.start, test.count, spi.DefaultRegisterConfig); err == nil {
+			t.Errorf("no error for %d registers from %#04x", test.count, test.start)
+		}
+		if len(d.Written()) != 0 {
+			t.Errorf("wrote to the device for %d registers from %#04x", test.count, test.start)
+		}
+	}
+
+	d := testdevice.New()
+	d.Respond([]byte{0x01})
+	if _, err := spi.DumpRegisters(d, 0xff, 1, spi.DefaultRegisterConfig); err != nil {
+		t.Errorf("unexpected error reading the last register: %s", err)
+	}
+}
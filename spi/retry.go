@@ -0,0 +1,92 @@
+package spi
+
+import (
+	"time"
+)
+
+// RetryPolicy describes when and how a retrying device reissues a failed
+// transfer.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a transfer is attempted,
+	// including the first attempt. Values less than one are treated as
+	// one, disabling retries.
+	MaxAttempts int
+
+	// Backoff is the time to wait before the first retry. Each subsequent
+	// retry waits twice as long as the one before it.
+	Backoff time.Duration
+
+	// Retryable decides whether a failed attempt should be retried, given
+	// the byte count and error it returned. If nil, an attempt is retried
+	// only if it reported that no bytes were transferred.
+	Retryable func(n int, err error) bool
+
+	// Clock, if set, is used to wait between attempts. If nil,
+	// SystemClock is used.
+	Clock Clock
+}
+
+func (p RetryPolicy) retryable(n int, err error) bool {
+	if p.Retryable == nil {
+		return n == 0
+	}
+	return p.Retryable(n, err)
+}
+
+func (p RetryPolicy) clock() Clock {
+	if p.Clock == nil {
+		return SystemClock
+	}
+	return p.Clock
+}
+
+// NewRetrying returns a Device that reissues failed Exchange and Request
+// calls on d according to the given policy, for platforms where transient
+// errors such as EINTR or EBUSY can be returned from a transfer.
+//
+// Retrying a transfer that had partially completed when it failed sends
+// its data again from the beginning, which many devices will interpret
+// differently than a single complete transfer. For that reason the
+// default policy retries only failures that reported no bytes
+// transferred; a custom Retryable func should be similarly careful.
+//
+// Other calls are passed through to d without retrying.
+func NewRetrying(d Device, policy RetryPolicy) Device {
+	return retrying{
+		ForwardingDevice: ForwardingDevice{Device: d},
+		policy:           policy,
+	}
+}
+
+type retrying struct {
+	ForwardingDevice
+	policy RetryPolicy
+}
+
+func (d retrying) Exchange(outData []byte, inData []byte) (int, error) {
+	return d.retry(func() (int, error) {
+		return d.Device.Exchange(outData, inData)
+	})
+}
+
+func (d retrying) Request(outData []byte, inData []byte) (int, error) {
+	return d.retry(func() (int, error) {
+		return d.Device.Request(outData, inData)
+	})
+}
+
+func (d retrying) retry(transfer func() (int, error)) (int, error) {
+	backoff := d.policy.Backoff
+	attempt := 1
+	for {
+		n, err := transfer()
+		if err == nil || attempt >= d.policy.MaxAttempts || !d.policy.retryable(n, err) {
+			return n, err
+		}
+		if backoff > 0 {
+			d.policy.clock().Sleep(backoff)
+			backoff *= 2
+		}
+		attempt++
+	}
+}
@@ -0,0 +1,97 @@
+package spi
+
+// ScatterGatherDevice is an optional interface implemented by devices that
+// can perform a single transfer whose data is split across several
+// non-contiguous buffers, such as a frame assembled from a header and a
+// payload, without first concatenating them.
+//
+// The buffers in out are sent in order as one continuous transfer, and the
+// data received is stored across the buffers in in, in order. If both
+// have a non-zero total length then the transfer is a full-duplex
+// exchange and the two totals must be equal, or the result is an error
+// wrapping ErrBufferLengthMismatch. Otherwise the transfer is a write or a
+// read, respectively.
+//
+// The returned count is the total number of bytes read into in, or for a
+// write the total number of bytes written.
+type ScatterGatherDevice interface {
+	TransferSG(out [][]byte, in [][]byte) (int, error)
+}
+
+// TransferSG performs a scatter-gather transfer on the given device, with
+// the same meaning as ScatterGatherDevice.TransferSG.
+//
+// If d implements ScatterGatherDevice then its native support is used.
+// Otherwise a write or read on a BatchDevice is submitted as one segment
+// per buffer with chip-select held between them, and any other transfer
+// copies the buffers to and from contiguous temporary buffers and uses
+// Exchange, Write or Read.
+func TransferSG(d Device, out [][]byte, in [][]byte) (int, error) {
+	if sg, ok := d.(ScatterGatherDevice); ok {
+		return sg.TransferSG(out, in)
+	}
+
+	outLen, inLen := totalLen(out), totalLen(in)
+	if outLen != 0 && inLen != 0 && outLen != inLen {
+		return 0, ErrBufferLengthMismatch
+	}
+	if bd, ok := d.(BatchDevice); ok && (outLen == 0 || inLen == 0) {
+		var segments []Segment
+		for _, buf := range out {
+			segments = append(segments, Segment{Out: buf, KeepCS: true})
+		}
+		for _, buf := range in {
+			segments = append(segments, Segment{In: buf, KeepCS: true})
+		}
+		if len(segments) == 0 {
+			return 0, nil
+		}
+		segments[len(segments)-1].KeepCS = false
+		if err := bd.Transfer(segments); err != nil {
+			return 0, err
+		}
+		return outLen + inLen, nil
+	}
+
+	switch {
+	case outLen != 0 && inLen != 0:
+		inBuf := make([]byte, inLen)
+		n, err := d.Exchange(gather(out, outLen), inBuf)
+		scatter(in, received(inBuf, n))
+		return n, err
+	case outLen != 0:
+		return d.Write(gather(out, outLen))
+	case inLen != 0:
+		inBuf := make([]byte, inLen)
+		n, err := d.Read(inBuf)
+		scatter(in, received(inBuf, n))
+		return n, err
+	default:
+		return 0, nil
+	}
+}
+
+func totalLen(bufs [][]byte) int {
+	n := 0
+	for _, buf := range bufs {
+		n += len(buf)
+	}
+	return n
+}
+
+// gather concatenates the given buffers, whose total length is n.
+func gather(bufs [][]byte, n int) []byte {
+	ret := make([]byte, 0, n)
+	for _, buf := range bufs {
+		ret = append(ret, buf...)
+	}
+	return ret
+}
+
+// scatter copies data across the given buffers in order, stopping when data
+// is exhausted.
+func scatter(bufs [][]byte, data []byte) {
+	for _, buf := range bufs {
+		data = data[copy(buf, data):]
+	}
+}
@@ -0,0 +1,31 @@
+package spi_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+func TestTransferSGFallback(t *testing.T) {
+	td := testdevice.New()
+	td.Respond([]byte{0xaa, 0xbb, 0xcc})
+	a, b := make([]byte, 1), make([]byte, 2)
+	n, err := spi.TransferSG(td, [][]byte{{0x01, 0x02}, {0x03}}, [][]byte{a, b})
+	if err != nil || n != 3 {
+		t.Fatalf("got %d, %v; want 3 and no error", n, err)
+	}
+	if got, want := td.Written(), []byte{0x01, 0x02, 0x03}; !bytes.Equal(got, want) {
+		t.Errorf("wrote [% x]; want [% x]", got, want)
+	}
+	if !bytes.Equal(a, []byte{0xaa}) || !bytes.Equal(b, []byte{0xbb, 0xcc}) {
+		t.Errorf("scattered [% x] and [% x]", a, b)
+	}
+}
+
+func TestTransferSGOverreported(t *testing.T) {
+	d := overreportingDevice{testdevice.New()}
+	spi.TransferSG(d, [][]byte{{0x01}}, [][]byte{make([]byte, 1)})
+	spi.TransferSG(d, nil, [][]byte{make([]byte, 1)})
+}
@@ -0,0 +1,74 @@
+package spi
+
+// R1Response is the R1 response token that an SD card in SPI mode sends
+// after each command, decoded into its flag bits. R1 is also the first
+// byte of the longer R3 and R7 responses.
+type R1Response struct {
+	// Raw is the response byte exactly as received.
+	Raw byte
+
+	InIdleState        bool
+	EraseReset         bool
+	IllegalCommand     bool
+	CommandCRCError    bool
+	EraseSequenceError bool
+	AddressError       bool
+	ParameterError     bool
+}
+
+// DecodeR1 decodes the given R1 response byte. The most significant bit of
+// a valid response is always zero.
+func DecodeR1(b byte) R1Response {
+	return R1Response{
+		Raw:                b,
+		InIdleState:        b&0x01 != 0,
+		EraseReset:         b&0x02 != 0,
+		IllegalCommand:     b&0x04 != 0,
+		CommandCRCError:    b&0x08 != 0,
+		EraseSequenceError: b&0x10 != 0,
+		AddressError:       b&0x20 != 0,
+		ParameterError:     b&0x40 != 0,
+	}
+}
+
+// OK returns true if the response reports no errors. The card may still be
+// in the idle state.
+func (r R1Response) OK() bool {
+	return r.Raw&0x7e == 0
+}
+
+// DefaultR1MaxBytes is the number of bytes ReadR1 waits for a response,
+// which is the maximum response delay (NCR) allowed by the SD
+// specification.
+const DefaultR1MaxBytes = 8
+
+// ReadR1 waits for and reads an R1 response token from an SD card in SPI
+// mode, after a command has been sent, waiting up to DefaultR1MaxBytes.
+// See ReadR1Limit.
+func ReadR1(d Device) (R1Response, error) {
+	return ReadR1Limit(d, DefaultR1MaxBytes)
+}
+
+// ReadR1Limit is like ReadR1, but waits up to maxBytes bytes for the
+// response to begin.
+//
+// The card holds its data line high while busy, so this clocks out 0xff
+// bytes one at a time until it receives a byte whose most significant
+// bit is clear, which is the response token. If no token arrives within
+// maxBytes bytes it returns ErrResponseTimeout.
+//
+// Each byte is a separate transfer, so chip-select must be held asserted
+// across the command and the response, such as by using a Transaction or
+// ChipSelectController, for cards that require it.
+func ReadR1Limit(d Device, maxBytes int) (R1Response, error) {
+	for i := 0; i < maxBytes; i++ {
+		b, err := ExchangeByte(d, 0xff)
+		if err != nil {
+			return R1Response{}, err
+		}
+		if b&0x80 == 0 {
+			return DecodeR1(b), nil
+		}
+	}
+	return R1Response{}, ErrResponseTimeout
+}
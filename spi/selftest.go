@@ -0,0 +1,193 @@
+package spi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// selfTestLen is the number of bytes exchanged by SelfTest.
+const selfTestLen = 256
+
+// SelfTest checks that the device's data path is working by enabling the
+// controller's internal loopback mode, exchanging a pseudo-random pattern
+// and verifying that the same pattern is received.
+//
+// Loopback is enabled by setting LinuxFlagLoop via RawModeFlags, so this
+// is only meaningful for backends using the Linux flag layout. If d does
+// not implement RawModeFlags, or the backend does not accept the loopback
+// flag, SelfTest returns an error wrapping ErrNotSupported rather than
+// reporting a failure. The device's previous flags are restored before
+// returning.
+func SelfTest(d Device) (err error) {
+	restore, err := enableLoopback(d)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if rerr := restore(); err == nil {
+			err = rerr
+		}
+	}()
+
+	out := selfTestPattern(selfTestLen)
+	in := make([]byte, len(out))
+	if _, err := d.Exchange(out, in); err != nil {
+		return err
+	}
+	for i := range out {
+		if in[i] != out[i] {
+			return fmt.Errorf("loopback self-test failed: byte %d was sent as %#04x but received as %#04x", i, out[i], in[i])
+		}
+	}
+	return nil
+}
+
+// BenchResult describes the performance measured by Benchmark.
+type BenchResult struct {
+	// Bytes is the number of bytes exchanged, and Duration is the total
+	// time taken to exchange them.
+	Bytes    int64
+	Duration time.Duration
+
+	// BytesPerSec is the achieved throughput.
+	BytesPerSec float64
+
+	// Chunks is the number of exchanges performed, and MinLatency,
+	// MaxLatency and AvgLatency describe the time each took.
+	Chunks     int
+	MinLatency time.Duration
+	MaxLatency time.Duration
+	AvgLatency time.Duration
+}
+
+// benchChunkSize is the number of bytes exchanged at a time by Benchmark.
+const benchChunkSize = 4096
+
+// Benchmark measures the device's real performance by exchanging
+// totalBytes bytes of data with it, in chunks of up to 4096 bytes.
+//
+// If the device supports loopback mode, as described for SelfTest, then
+// it is enabled for the duration of the benchmark so that no real
+// peripheral is needed; the device's previous flags are restored
+// afterwards. Otherwise the data is exchanged with whatever is connected
+// to the bus, so the caller must ensure the peripheral will tolerate it.
+//
+// Cancellation of ctx is checked between chunks. If it is cancelled,
+// Benchmark returns the result measured so far along with the context's
+// error.
+func Benchmark(ctx context.Context, d Device, totalBytes int) (result BenchResult, err error) {
+	restore, err := enableLoopback(d)
+	switch {
+	case err == nil:
+		defer func() {
+			if rerr := restore(); err == nil {
+				err = rerr
+			}
+		}()
+	case errors.Is(err, ErrNotSupported):
+		// Benchmark against the real peripheral instead.
+	default:
+		return result, err
+	}
+
+	size := totalBytes
+	if size > benchChunkSize {
+		size = benchChunkSize
+	}
+	out := selfTestPattern(size)
+	in := make([]byte, size)
+
+	start := time.Now()
+	defer func() {
+		result.Duration = time.Since(start)
+		if result.Duration > 0 {
+			result.BytesPerSec = float64(result.Bytes) / result.Duration.Seconds()
+		}
+		if result.Chunks > 0 {
+			result.AvgLatency = result.Duration / time.Duration(result.Chunks)
+		}
+	}()
+
+	for remain := totalBytes; remain > 0; {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		n := remain
+		if n > size {
+			n = size
+		}
+		chunkStart := time.Now()
+		_, err := d.Exchange(out[:n], in[:n])
+		latency := time.Since(chunkStart)
+		if err != nil {
+			return result, err
+		}
+		if result.Chunks == 0 || latency < result.MinLatency {
+			result.MinLatency = latency
+		}
+		if latency > result.MaxLatency {
+			result.MaxLatency = latency
+		}
+		result.Chunks++
+		result.Bytes += int64(n)
+		remain -= n
+	}
+	return result, nil
+}
+
+// enableLoopback enables loopback mode on the given device, returning a
+// function that restores the device's previous flags. It returns an error
+// wrapping ErrNotSupported if the device cannot enable loopback.
+func enableLoopback(d Device) (restore func() error, err error) {
+	rf, ok := d.(RawModeFlags)
+	if !ok || !Capabilities(d).RawModeFlags {
+		return nil, NotSupported("loopback mode")
+	}
+	prev, err := rf.ModeFlags()
+	if err != nil {
+		return nil, loopbackNotSupported(err)
+	}
+	if err := rf.SetModeFlags(prev | LinuxFlagLoop); err != nil {
+		return nil, loopbackNotSupported(err)
+	}
+	restore = func() error {
+		return rf.SetModeFlags(prev)
+	}
+	now, err := rf.ModeFlags()
+	if err == nil && now&LinuxFlagLoop == 0 {
+		err = NotSupported("loopback mode")
+	} else if err != nil {
+		err = loopbackNotSupported(err)
+	}
+	if err != nil {
+		restore()
+		return nil, err
+	}
+	return restore, nil
+}
+
+// loopbackNotSupported wraps an error from enabling loopback mode so that
+// it also wraps ErrNotSupported, since the device could not enable it.
+func loopbackNotSupported(err error) error {
+	if errors.Is(err, ErrNotSupported) {
+		return err
+	}
+	return fmt.Errorf("loopback mode %w: %w", ErrNotSupported, err)
+}
+
+// selfTestPattern returns a fixed pseudo-random pattern of the given
+// length, generated with a simple xorshift so that every bit position
+// toggles frequently.
+func selfTestPattern(n int) []byte {
+	buf := make([]byte, n)
+	x := uint32(0x2545f491)
+	for i := range buf {
+		x ^= x << 13
+		x ^= x >> 17
+		x ^= x << 5
+		buf[i] = byte(x)
+	}
+	return buf
+}
@@ -0,0 +1,29 @@
+package spi_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+// brokenFlagsDevice implements RawModeFlags but fails to report its flags.
+type brokenFlagsDevice struct {
+	*testdevice.Device
+}
+
+func (d brokenFlagsDevice) SetModeFlags(flags uint32) error {
+	return nil
+}
+
+func (d brokenFlagsDevice) ModeFlags() (uint32, error) {
+	return 0, errors.New("ioctl failed")
+}
+
+func TestSelfTestFlagsError(t *testing.T) {
+	err := spi.SelfTest(brokenFlagsDevice{testdevice.New()})
+	if !errors.Is(err, spi.ErrNotSupported) {
+		t.Errorf("error %q does not wrap ErrNotSupported", err)
+	}
+}
@@ -0,0 +1,119 @@
+package spi
+
+import (
+	"fmt"
+	"time"
+)
+
+// SpeedInspector is an optional interface implemented by devices that can
+// report the clock speed they are actually using.
+//
+// Hardware often can only approximate the speed requested with
+// SetMaxSpeedHz, typically by choosing the nearest achievable clock
+// divisor, so the value returned by MaxSpeedHz may differ from the value
+// most recently passed to SetMaxSpeedHz. It reflects the speed the
+// implementation settled on after any such clamping.
+type SpeedInspector interface {
+	MaxSpeedHz() (uint32, error)
+}
+
+// SpeedOverrideDevice is an optional interface implemented by devices that
+// can perform an individual transfer at a speed other than the one set by
+// SetMaxSpeedHz, such as reading a device ID at a slow clock during
+// startup.
+//
+// ExchangeAt behaves like Exchange except that the transfer runs at no
+// more than speedHz. The speed configured with SetMaxSpeedHz is not
+// changed and remains the default for other transfers. If speedHz is zero
+// then the configured default speed is used, making the call equivalent
+// to Exchange.
+type SpeedOverrideDevice interface {
+	ExchangeAt(speedHz uint32, outData []byte, inData []byte) (n int, err error)
+}
+
+// SpeedRange is an optional interface implemented by devices that can
+// report the range of clock speeds their hardware supports, so that
+// callers can validate or clamp a speed before passing it to
+// SetMaxSpeedHz.
+//
+// The ceiling is reported by MaxSupportedSpeedHz rather than MaxSpeedHz,
+// since the latter reports the currently-configured speed as part of
+// SpeedInspector. Either method returns zero if the corresponding limit
+// is unknown or unbounded.
+type SpeedRange interface {
+	MinSpeedHz() uint32
+	MaxSupportedSpeedHz() uint32
+}
+
+// ClosestDivisor returns the integer clock divisor that a typical SPI
+// controller would choose to produce the given requested speed from a
+// base clock of baseHz, along with the actual speed that results.
+//
+// Because SetMaxSpeedHz sets a maximum, the divisor is rounded up where
+// necessary so that the actual speed never exceeds the requested speed.
+// The divisor is at least one, so requested speeds at or above the base
+// clock result in the base clock itself. If either argument is zero then
+// no divisor can be chosen and both results are zero.
+//
+// Real controllers often restrict divisors further, such as to powers of
+// two or even numbers, so backends with such restrictions cannot use this
+// directly.
+func ClosestDivisor(baseHz, requestedHz uint32) (divisor uint32, actualHz uint32) {
+	if baseHz == 0 || requestedHz == 0 {
+		return 0, 0
+	}
+	divisor = baseHz / requestedHz
+	if divisor == 0 {
+		divisor = 1
+	} else if baseHz%requestedHz != 0 {
+		divisor++
+	}
+	return divisor, baseHz / divisor
+}
+
+// SpeedDefault can be passed to SetMaxSpeedHz to explicitly request the
+// backend's default speed, rather than a specific maximum.
+//
+// Zero is not accepted for that purpose because it is far more likely to
+// be the result of an uninitialized configuration than a deliberate
+// choice. See ValidateSpeed.
+const SpeedDefault uint32 = 0xffffffff
+
+// ValidateSpeed returns an error wrapping ErrInvalidSpeed if the given
+// speed is zero, or nil otherwise. Implementations of Configurator should
+// call this at the start of SetMaxSpeedHz so that an uninitialized speed
+// is reported immediately rather than interpreted in some backend-specific
+// way.
+func ValidateSpeed(hz uint32) error {
+	if hz == 0 {
+		return fmt.Errorf("%w: speed must be non-zero; use SpeedDefault for the backend's default", ErrInvalidSpeed)
+	}
+	return nil
+}
+
+// TransferDuration estimates the time taken on the wire to transfer
+// byteCount bytes of buffer data at the given clock speed, where each word
+// has the given number of bits and occupies buffer bytes as described for
+// WordSizeConfigurator. A bitsPerWord of zero is treated as eight.
+//
+// The result is an idealized lower bound that counts only clock cycles
+// carrying data. It excludes chip-select setup and hold times, any
+// configured delays, and the overhead of the backend and operating system.
+// If speedHz is zero then the duration cannot be estimated and zero is
+// returned.
+func TransferDuration(byteCount int, bitsPerWord uint8, speedHz uint32) time.Duration {
+	if speedHz == 0 || byteCount <= 0 {
+		return 0
+	}
+	if bitsPerWord == 0 {
+		bitsPerWord = 8
+	}
+	bytesPerWord := (int64(bitsPerWord) + 7) / 8
+	bits := int64(byteCount) / bytesPerWord * int64(bitsPerWord)
+	hz := int64(speedHz)
+	// Whole seconds are separated out to avoid overflow, and the
+	// remainder is rounded up so the estimate is never too short.
+	whole := bits / hz * int64(time.Second)
+	part := (bits%hz*int64(time.Second) + hz - 1) / hz
+	return time.Duration(whole + part)
+}
@@ -0,0 +1,55 @@
+package spi_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apparentlymart/go-spi/spi"
+)
+
+func TestClosestDivisor(t *testing.T) {
+	tests := []struct {
+		baseHz, requestedHz uint32
+		divisor, actualHz   uint32
+	}{
+		{48000000, 12000000, 4, 12000000},
+		{48000000, 10000000, 5, 9600000},
+		{48000000, 48000000, 1, 48000000},
+		{48000000, 100000000, 1, 48000000},
+		{48000000, 1, 48000000, 1},
+		{0, 1000000, 0, 0},
+		{48000000, 0, 0, 0},
+	}
+	for _, test := range tests {
+		divisor, actualHz := spi.ClosestDivisor(test.baseHz, test.requestedHz)
+		if divisor != test.divisor || actualHz != test.actualHz {
+			t.Errorf("ClosestDivisor(%d, %d) = %d, %d; want %d, %d", test.baseHz, test.requestedHz, divisor, actualHz, test.divisor, test.actualHz)
+		}
+		if actualHz > test.requestedHz {
+			t.Errorf("ClosestDivisor(%d, %d) gives %d Hz, faster than requested", test.baseHz, test.requestedHz, actualHz)
+		}
+	}
+}
+
+func TestTransferDuration(t *testing.T) {
+	tests := []struct {
+		byteCount   int
+		bitsPerWord uint8
+		speedHz     uint32
+		want        time.Duration
+	}{
+		{1000, 8, 1000000, 8 * time.Millisecond},
+		{1000, 0, 1000000, 8 * time.Millisecond},
+		{10, 12, 1000000, 60 * time.Microsecond},
+		{1, 8, 3, 2666666667 * time.Nanosecond},
+		{1 << 30, 8, 1, (1 << 33) * time.Second},
+		{1000, 8, 0, 0},
+		{0, 8, 1000000, 0},
+		{-1, 8, 1000000, 0},
+	}
+	for _, test := range tests {
+		if got := spi.TransferDuration(test.byteCount, test.bitsPerWord, test.speedHz); got != test.want {
+			t.Errorf("TransferDuration(%d, %d, %d) = %s; want %s", test.byteCount, test.bitsPerWord, test.speedHz, got, test.want)
+		}
+	}
+}
@@ -5,6 +5,7 @@
 package spi
 
 import (
+	"fmt"
 	"io"
 )
 
@@ -22,11 +23,87 @@ const (
 	Mode3 Mode = 3
 )
 
+// These aliases name each of the standard modes by its clock polarity
+// (CPOL) and clock phase (CPHA), matching the SPI_CPOL and SPI_CPHA flags
+// used by C drivers. See also ModeFromCPOL.
+const (
+	ModeCPOL0CPHA0 = Mode0
+	ModeCPOL0CPHA1 = Mode1
+	ModeCPOL1CPHA0 = Mode2
+	ModeCPOL1CPHA1 = Mode3
+)
+
 const (
 	MsbFirst BitOrder = 0
 	LsbFirst BitOrder = 1
 )
 
+// String returns a name for the mode along with its clock polarity (CPOL)
+// and clock phase (CPHA) settings, like "Mode2 (CPOL=1 CPHA=0)".
+//
+// Values outside of the four standard modes are returned as e.g. "Mode(7)".
+func (m Mode) String() string {
+	if !m.Valid() {
+		return fmt.Sprintf("Mode(%d)", uint(m))
+	}
+	return fmt.Sprintf("Mode%d (CPOL=%d CPHA=%d)", uint(m), uint(m>>1), uint(m&1))
+}
+
+// Valid returns true if the mode is one of the four standard modes.
+func (m Mode) Valid() bool {
+	return m <= Mode3
+}
+
+// ValidateMode returns an error wrapping ErrInvalidMode if the given mode
+// is not one of the four standard modes, or nil otherwise. Implementations
+// of Configurator should call this at the start of SetMode so that an
+// invalid mode is reported immediately rather than causing undefined
+// behavior.
+func ValidateMode(m Mode) error {
+	if !m.Valid() {
+		return fmt.Errorf("%w: %s", ErrInvalidMode, m)
+	}
+	return nil
+}
+
+// CPOL returns true if the mode's clock idles high, which is the case for
+// Mode2 and Mode3.
+func (m Mode) CPOL() bool {
+	return m&2 != 0
+}
+
+// CPHA returns true if the mode samples data on the trailing clock edge
+// rather than the leading edge, which is the case for Mode1 and Mode3.
+func (m Mode) CPHA() bool {
+	return m&1 != 0
+}
+
+// ModeFromCPOL returns the standard mode with the given clock polarity
+// and clock phase, as often given separately in device datasheets.
+// For example, ModeFromCPOL(true, true) returns Mode3.
+func ModeFromCPOL(cpol, cpha bool) Mode {
+	var m Mode
+	if cpol {
+		m |= 2
+	}
+	if cpha {
+		m |= 1
+	}
+	return m
+}
+
+// String returns the name of the bit order, either "MsbFirst" or "LsbFirst".
+func (o BitOrder) String() string {
+	switch o {
+	case MsbFirst:
+		return "MsbFirst"
+	case LsbFirst:
+		return "LsbFirst"
+	default:
+		return fmt.Sprintf("BitOrder(%d)", uint(o))
+	}
+}
+
 // Configurator includes all of the configuration functions that are expected
 // to be available on all SPI channels.
 type Configurator interface {
@@ -35,6 +112,12 @@ type Configurator interface {
 	SetMaxSpeedHz(speed uint32) error
 }
 
+// ModeInspector is an optional interface implemented by devices that can
+// report the mode they are currently configured to use.
+type ModeInspector interface {
+	Mode() (Mode, error)
+}
+
 // ReadableDevice represents an SPI device that can only be written to.
 type WritableDevice interface {
 	Configurator
@@ -54,6 +137,13 @@ type ReadableDevice interface {
 // or ReadableDevice if data will pass only in one direction, so it is
 // clear to the programmer how the SPI bus will be used and thus e.g. whether
 // the MISO pin needs to be connected.
+//
+// Each transfer method returns the number of bytes transferred, which for
+// Exchange and Request is the number of bytes read into inData. A
+// transfer that completes in full returns the full length and a nil
+// error. A short transfer must return a non-nil error, such as one
+// wrapping ErrShortTransfer, along with the number of bytes that were
+// completed; implementations can use CheckTransfer to ensure this.
 type Device interface {
 	Configurator
 	io.Writer
@@ -0,0 +1,348 @@
+package spi
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// NewStreamDevice returns a Device that performs its operations by
+// exchanging messages with a peer over the given stream, such as a serial
+// link to an SPI bridge or a network connection to a remote host running
+// ServeStream.
+//
+// Each call sends one request message and waits for one response message,
+// so calls are serialized. Closing the device closes the stream.
+//
+// Request messages consist of a 13-byte header followed by the data to
+// write:
+//
+//	op      1 byte   operation code, described below
+//	arg     4 bytes  big-endian argument for configuration operations
+//	outLen  4 bytes  big-endian number of bytes to write
+//	inLen   4 bytes  big-endian number of bytes to read
+//	out     outLen bytes of data to write
+//
+// The operation codes are the ASCII characters 'M' (SetMode), 'B'
+// (SetBitOrder), 'S' (SetMaxSpeedHz), 'W' (Write), 'R' (Read), 'X'
+// (Exchange) and 'Q' (Request). arg is the mode, bit order or speed for
+// the configuration operations and zero otherwise, while outLen and inLen
+// are zero where an operation does not transfer data in that direction.
+// For 'X' they are always equal.
+//
+// Response messages consist of a 5-byte header followed by a body:
+//
+//	status  1 byte   0 for success or 1 for failure
+//	n       4 bytes  big-endian count returned by the operation
+//
+// n may not exceed the larger of outLen and inLen. On success, the body
+// is exactly inLen bytes of data read. On failure, the body is a 1-byte
+// error code followed by a 4-byte big-endian length and that many bytes
+// of UTF-8 error message, and no data.
+//
+// The error code identifies which of the following errors the failure
+// wraps, if any, so that errors.Is gives the same answer on both sides
+// of the stream: 0 (none of these), 1 (ErrNotSupported), 2
+// (ErrInvalidMode), 3 (ErrInvalidSpeed), 4 (ErrBufferLengthMismatch), 5
+// (ErrTimeout), 6 (ErrShortTransfer), 7 (ErrHalfDuplex) or 8 (io.EOF).
+// Any other identity of the original error, such as its concrete type,
+// is lost.
+//
+// Neither side accepts a message whose data or error message is longer
+// than MaxStreamMessageLen.
+func NewStreamDevice(rw io.ReadWriteCloser) ClosableDevice {
+	return &streamDevice{rw: rw}
+}
+
+// MaxStreamMessageLen is the maximum length of any data or error message
+// in a single message of the protocol used by NewStreamDevice and
+// ServeStream.
+const MaxStreamMessageLen = 1 << 24
+
+const (
+	streamOpSetMode     = 'M'
+	streamOpSetBitOrder = 'B'
+	streamOpSetSpeed    = 'S'
+	streamOpWrite       = 'W'
+	streamOpRead        = 'R'
+	streamOpExchange    = 'X'
+	streamOpRequest     = 'Q'
+
+	streamStatusOK    = 0
+	streamStatusError = 1
+)
+
+// streamErrors are the errors whose identity survives the stream
+// protocol, indexed by their error code. Code zero means none of them, so
+// new errors may only be appended.
+var streamErrors = []error{
+	nil,
+	ErrNotSupported,
+	ErrInvalidMode,
+	ErrInvalidSpeed,
+	ErrBufferLengthMismatch,
+	ErrTimeout,
+	ErrShortTransfer,
+	ErrHalfDuplex,
+	io.EOF,
+}
+
+// streamError is an error received from the peer of a stream device.
+type streamError struct {
+	msg string
+	err error
+}
+
+func (e *streamError) Error() string {
+	return e.msg
+}
+
+func (e *streamError) Unwrap() error {
+	return e.err
+}
+
+// streamErrorCode returns the code that represents err in the stream
+// protocol.
+func streamErrorCode(err error) byte {
+	for code, target := range streamErrors[1:] {
+		if errors.Is(err, target) {
+			return byte(code + 1)
+		}
+	}
+	return 0
+}
+
+type streamDevice struct {
+	mu sync.Mutex
+	rw io.ReadWriteCloser
+}
+
+func (d *streamDevice) SetMode(mode Mode) error {
+	if err := ValidateMode(mode); err != nil {
+		return err
+	}
+	_, err := d.call(streamOpSetMode, uint32(mode), nil, nil)
+	return err
+}
+
+func (d *streamDevice) SetBitOrder(order BitOrder) error {
+	_, err := d.call(streamOpSetBitOrder, uint32(order), nil, nil)
+	return err
+}
+
+func (d *streamDevice) SetMaxSpeedHz(speed uint32) error {
+	if err := ValidateSpeed(speed); err != nil {
+		return err
+	}
+	_, err := d.call(streamOpSetSpeed, speed, nil, nil)
+	return err
+}
+
+func (d *streamDevice) Write(data []byte) (int, error) {
+	n, err := d.call(streamOpWrite, 0, data, nil)
+	return CheckTransfer("Write", n, len(data), err)
+}
+
+func (d *streamDevice) Read(data []byte) (int, error) {
+	n, err := d.call(streamOpRead, 0, nil, data)
+	return CheckTransfer("Read", n, len(data), err)
+}
+
+func (d *streamDevice) Exchange(outData []byte, inData []byte) (int, error) {
+	if err := ValidateExchange(outData, inData); err != nil {
+		return 0, err
+	}
+	n, err := d.call(streamOpExchange, 0, outData, inData)
+	return CheckTransfer("Exchange", n, len(inData), err)
+}
+
+func (d *streamDevice) Request(outData []byte, inData []byte) (int, error) {
+	n, err := d.call(streamOpRequest, 0, outData, inData)
+	return CheckTransfer("Request", n, len(inData), err)
+}
+
+func (d *streamDevice) Close() error {
+	return d.rw.Close()
+}
+
+func (d *streamDevice) call(op byte, arg uint32, out, in []byte) (int, error) {
+	if len(out) > MaxStreamMessageLen || len(in) > MaxStreamMessageLen {
+		return 0, fmt.Errorf("stream transfer may not exceed %d bytes", MaxStreamMessageLen)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	msg := make([]byte, 13, 13+len(out))
+	msg[0] = op
+	binary.BigEndian.PutUint32(msg[1:], arg)
+	binary.BigEndian.PutUint32(msg[5:], uint32(len(out)))
+	binary.BigEndian.PutUint32(msg[9:], uint32(len(in)))
+	msg = append(msg, out...)
+	if _, err := d.rw.Write(msg); err != nil {
+		return 0, err
+	}
+
+	var hdr [5]byte
+	if _, err := io.ReadFull(d.rw, hdr[:]); err != nil {
+		return 0, err
+	}
+	n := int(binary.BigEndian.Uint32(hdr[1:]))
+	maxN := len(in)
+	if len(out) > maxN {
+		maxN = len(out)
+	}
+	if n > maxN {
+		return 0, fmt.Errorf("stream response reports %d bytes transferred, but at most %d were requested", n, maxN)
+	}
+	switch hdr[0] {
+	case streamStatusOK:
+		if _, err := io.ReadFull(d.rw, in); err != nil {
+			return 0, err
+		}
+		return n, nil
+	case streamStatusError:
+		var code [1]byte
+		if _, err := io.ReadFull(d.rw, code[:]); err != nil {
+			return 0, err
+		}
+		msg, err := readStreamBlock(d.rw)
+		if err != nil {
+			return 0, err
+		}
+		if int(code[0]) >= len(streamErrors) {
+			return 0, fmt.Errorf("invalid stream error code %d", code[0])
+		}
+		target := streamErrors[code[0]]
+		if target == io.EOF {
+			// The io.Reader contract requires EOF itself, not
+			// something wrapping it.
+			return n, io.EOF
+		}
+		return n, &streamError{msg: string(msg), err: target}
+	default:
+		return 0, fmt.Errorf("invalid stream response status %d", hdr[0])
+	}
+}
+
+// ServeStream reads request messages from the given stream, performs them
+// on d, and writes back response messages, using the protocol described
+// for NewStreamDevice. It is the counterpart that allows a device on one
+// host to be used from another.
+//
+// ServeStream returns nil when the stream reaches end of file between
+// messages, or the first error encountered reading from or writing to
+// the stream. Errors from d itself are reported to the peer instead.
+func ServeStream(rw io.ReadWriter, d Device) error {
+	for {
+		var hdr [13]byte
+		if _, err := io.ReadFull(rw, hdr[:]); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		op := hdr[0]
+		arg := binary.BigEndian.Uint32(hdr[1:])
+		outLen := binary.BigEndian.Uint32(hdr[5:])
+		inLen := binary.BigEndian.Uint32(hdr[9:])
+		if outLen > MaxStreamMessageLen || inLen > MaxStreamMessageLen {
+			return fmt.Errorf("stream request exceeds %d bytes", MaxStreamMessageLen)
+		}
+		out := make([]byte, outLen)
+		if _, err := io.ReadFull(rw, out); err != nil {
+			return err
+		}
+		in := make([]byte, inLen)
+
+		var n int
+		var err error
+		switch op {
+		case streamOpSetMode:
+			err = d.SetMode(Mode(arg))
+		case streamOpSetBitOrder:
+			err = d.SetBitOrder(BitOrder(arg))
+		case streamOpSetSpeed:
+			err = d.SetMaxSpeedHz(arg)
+		case streamOpWrite:
+			n, err = d.Write(out)
+		case streamOpRead:
+			n, err = d.Read(in)
+		case streamOpExchange:
+			n, err = d.Exchange(out, in)
+		case streamOpRequest:
+			n, err = d.Request(out, in)
+		default:
+			err = fmt.Errorf("unsupported stream operation %q", op)
+		}
+
+		resp := make([]byte, 5)
+		binary.BigEndian.PutUint32(resp[1:], uint32(n))
+		if err != nil {
+			msg := err.Error()
+			if len(msg) > MaxStreamMessageLen {
+				msg = msg[:MaxStreamMessageLen]
+			}
+			resp[0] = streamStatusError
+			resp = append(resp, streamErrorCode(err))
+			resp = binary.BigEndian.AppendUint32(resp, uint32(len(msg)))
+			resp = append(resp, msg...)
+		} else {
+			resp[0] = streamStatusOK
+			resp = append(resp, in...)
+		}
+		if _, err := rw.Write(resp); err != nil {
+			return err
+		}
+	}
+}
+
+func readStreamBlock(r io.Reader) ([]byte, error) {
+	var lenBuf [4]byte
+	if _, err := io.ReadFull(r, lenBuf[:]); err != nil {
+		return nil, err
+	}
+	l := binary.BigEndian.Uint32(lenBuf[:])
+	if l > MaxStreamMessageLen {
+		return nil, fmt.Errorf("stream message exceeds %d bytes", MaxStreamMessageLen)
+	}
+	buf := make([]byte, l)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// ConnAdapter returns an io.ReadWriteCloser that exposes the given device
+// as a plain byte stream, so that it can be connected to code that works
+// with streams, such as io.Copy to or from a network connection. Reads and
+// writes on the stream are passed directly to the device's Read and Write
+// methods, and Close closes the device if it implements io.Closer.
+//
+// A stream has no framing, so the boundaries between transfers are lost:
+// a single Write on the other end of a network connection may arrive as
+// several Writes to the device, each its own transaction on the bus, and
+// there is no way to express Exchange or Request or configuration calls.
+// Where those matter, serve the device with ServeStream on the hardware
+// host and use it with NewStreamDevice on the controlling host instead.
+func ConnAdapter(d Device) io.ReadWriteCloser {
+	return connAdapter{d}
+}
+
+type connAdapter struct {
+	dev Device
+}
+
+func (c connAdapter) Read(p []byte) (int, error) {
+	return c.dev.Read(p)
+}
+
+func (c connAdapter) Write(p []byte) (int, error) {
+	return c.dev.Write(p)
+}
+
+func (c connAdapter) Close() error {
+	return Close(c.dev)
+}
@@ -0,0 +1,43 @@
+package spi_test
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+func TestStreamDeviceErrorIdentity(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	go spi.ServeStream(server, testdevice.FailAfter(0, spi.NotSupported("widgets")))
+
+	d := spi.NewStreamDevice(client)
+	_, err := d.Write([]byte{0x01})
+	if !errors.Is(err, spi.ErrNotSupported) {
+		t.Errorf("error %q does not wrap ErrNotSupported", err)
+	}
+}
+
+func TestStreamDeviceInvalidCount(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	go func() {
+		// A broken peer that claims to have read more than requested.
+		var req [13]byte
+		io.ReadFull(server, req[:])
+		resp := []byte{0, 0, 0, 0, 0, 0}
+		binary.BigEndian.PutUint32(resp[1:], 100)
+		server.Write(resp)
+	}()
+
+	d := spi.NewStreamDevice(client)
+	n, err := d.Read(make([]byte, 1))
+	if err == nil || n != 0 {
+		t.Errorf("got %d, %v; want an error", n, err)
+	}
+}
@@ -0,0 +1,109 @@
+package spi
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// StreamOptions customizes the behavior of StreamWithOptions.
+type StreamOptions struct {
+	// Cmd, if set, is written before each frame is read, as a single
+	// Request, for devices that must be told to take each sample.
+	Cmd []byte
+
+	// Buffer is the number of frames that can be waiting on the frames
+	// channel before the consumer falls behind.
+	Buffer int
+
+	// DropWhenFull selects what happens when the consumer falls behind.
+	// By default the stream stops issuing transfers until there is room
+	// on the channel, which may cause the device to lose samples. If
+	// DropWhenFull is set then transfers continue and frames that do not
+	// fit are discarded instead.
+	DropWhenFull bool
+}
+
+// Stream continuously reads frames of frameSize bytes from the device in a
+// background goroutine and emits them on the returned frames channel, for
+// consuming a device that produces data continuously, such as a sampling
+// ADC, using idiomatic channel operations.
+//
+// It is equivalent to StreamWithOptions with the zero StreamOptions, which
+// issues each read as a Request with nothing written and blocks while the
+// consumer is behind.
+func Stream(ctx context.Context, d Device, frameSize int) (<-chan []byte, <-chan error) {
+	return StreamWithOptions(ctx, d, frameSize, StreamOptions{})
+}
+
+// StreamWithOptions is like Stream but with options to set a command to
+// send before each frame and the handling of a consumer that falls
+// behind.
+//
+// Each frame is a newly allocated slice that the consumer may keep. The
+// stream runs until ctx is cancelled or a transfer fails, at which point
+// the frames channel is closed and the reason the stream stopped,
+// either the context's error or the transfer's error, is sent on the
+// errors channel, which is then closed too. The errors channel is
+// buffered so that the stream can stop even if nothing receives from it.
+//
+// A frame that the device returns short also stops the stream, with
+// io.ErrUnexpectedEOF. If frameSize is not positive then the stream does
+// not start at all: the frames channel is closed immediately and the
+// errors channel reports the invalid size.
+//
+// The device must not be used for anything else while the stream is
+// running, unless it is safe for concurrent use.
+func StreamWithOptions(ctx context.Context, d Device, frameSize int, opts StreamOptions) (<-chan []byte, <-chan error) {
+	buffer := opts.Buffer
+	if buffer < 0 {
+		buffer = 0
+	}
+	frames := make(chan []byte, buffer)
+	errs := make(chan error, 1)
+
+	if frameSize <= 0 {
+		close(frames)
+		errs <- fmt.Errorf("invalid stream frame size %d", frameSize)
+		close(errs)
+		return frames, errs
+	}
+
+	go func() {
+		err := stream(ctx, d, frameSize, opts, frames)
+		close(frames)
+		errs <- err
+		close(errs)
+	}()
+	return frames, errs
+}
+
+// stream performs the work of StreamWithOptions, returning the reason it
+// stopped.
+func stream(ctx context.Context, d Device, frameSize int, opts StreamOptions, frames chan<- []byte) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		frame := make([]byte, frameSize)
+		n, err := d.Request(opts.Cmd, frame)
+		if err != nil {
+			return err
+		}
+		if n < frameSize {
+			return io.ErrUnexpectedEOF
+		}
+		if opts.DropWhenFull {
+			select {
+			case frames <- frame:
+			default:
+			}
+			continue
+		}
+		select {
+		case frames <- frame:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package spi_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+func TestStreamInvalidFrameSize(t *testing.T) {
+	d := testdevice.New()
+	frames, errs := spi.Stream(context.Background(), d, 0)
+	if _, ok := <-frames; ok {
+		t.Errorf("received a frame from a stream with no frame size")
+	}
+	if err := <-errs; err == nil {
+		t.Errorf("no error for a stream with no frame size")
+	}
+	if len(d.Written()) != 0 {
+		t.Errorf("stream used the device")
+	}
+}
@@ -0,0 +1,68 @@
+package spi
+
+import (
+	"sync"
+)
+
+// NewSynchronized returns a Device that serializes all calls to the given
+// device using a mutex, so that it can be safely shared between
+// goroutines.
+//
+// This only prevents calls from interleaving with each other. A caller
+// that needs several calls to happen together without another goroutine's
+// calls intervening, such as configuring the device and then transferring
+// data, must arrange its own locking around the whole sequence.
+func NewSynchronized(d Device) Device {
+	return &synchronized{dev: d}
+}
+
+type synchronized struct {
+	mu  sync.Mutex
+	dev Device
+}
+
+func (d *synchronized) Unwrap() Device {
+	return d.dev
+}
+
+func (d *synchronized) SetMode(mode Mode) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.dev.SetMode(mode)
+}
+
+func (d *synchronized) SetBitOrder(order BitOrder) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.dev.SetBitOrder(order)
+}
+
+func (d *synchronized) SetMaxSpeedHz(speed uint32) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.dev.SetMaxSpeedHz(speed)
+}
+
+func (d *synchronized) Write(data []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.dev.Write(data)
+}
+
+func (d *synchronized) Read(data []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.dev.Read(data)
+}
+
+func (d *synchronized) Exchange(outData []byte, inData []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.dev.Exchange(outData, inData)
+}
+
+func (d *synchronized) Request(outData []byte, inData []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.dev.Request(outData, inData)
+}
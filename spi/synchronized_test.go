@@ -0,0 +1,95 @@
+package spi_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+)
+
+// unsafeDevice is a Device with no locking of its own, so that the race
+// detector reports any calls that reach it concurrently.
+type unsafeDevice struct {
+	mode    spi.Mode
+	order   spi.BitOrder
+	speed   uint32
+	calls   int
+	written int
+}
+
+func (d *unsafeDevice) SetMode(mode spi.Mode) error {
+	d.calls++
+	d.mode = mode
+	return nil
+}
+
+func (d *unsafeDevice) SetBitOrder(order spi.BitOrder) error {
+	d.calls++
+	d.order = order
+	return nil
+}
+
+func (d *unsafeDevice) SetMaxSpeedHz(speed uint32) error {
+	d.calls++
+	d.speed = speed
+	return nil
+}
+
+func (d *unsafeDevice) Write(data []byte) (int, error) {
+	d.calls++
+	d.written += len(data)
+	return len(data), nil
+}
+
+func (d *unsafeDevice) Read(data []byte) (int, error) {
+	d.calls++
+	return len(data), nil
+}
+
+func (d *unsafeDevice) Exchange(outData []byte, inData []byte) (int, error) {
+	d.calls++
+	d.written += len(outData)
+	return len(inData), nil
+}
+
+func (d *unsafeDevice) Request(outData []byte, inData []byte) (int, error) {
+	d.calls++
+	d.written += len(outData)
+	return len(inData), nil
+}
+
+// TestSynchronizedConcurrent is most useful when run with -race.
+func TestSynchronizedConcurrent(t *testing.T) {
+	const goroutines = 8
+	const iterations = 200
+
+	inner := &unsafeDevice{}
+	d := spi.NewSynchronized(inner)
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			out := []byte{byte(g), 0x00}
+			in := make([]byte, 2)
+			for i := 0; i < iterations; i++ {
+				d.SetMode(spi.Mode(i % 4))
+				d.SetBitOrder(spi.MsbFirst)
+				d.SetMaxSpeedHz(uint32(1000 * (g + 1)))
+				d.Write(out)
+				d.Read(in)
+				d.Exchange(out, in)
+				d.Request(out[:1], in)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if got, want := inner.calls, goroutines*iterations*7; got != want {
+		t.Errorf("inner device got %d calls, want %d", got, want)
+	}
+	if got, want := inner.written, goroutines*iterations*5; got != want {
+		t.Errorf("inner device got %d bytes written, want %d", got, want)
+	}
+}
@@ -0,0 +1,80 @@
+package testdevice
+
+import (
+	"bytes"
+
+	"github.com/apparentlymart/go-spi/spi"
+)
+
+// BufferDevice is a deliberately simple spi.Device backed by a pair of
+// byte buffers, intended for runnable documentation examples and for
+// experimenting with the spi package without hardware.
+//
+// Every byte written by Write, Exchange or Request is appended to Tx, and
+// every byte read by Read, Exchange or Request is taken in order from the
+// bytes given to FromBuffers. Once those are exhausted, reads produce
+// zero bytes. Configuration calls succeed for any valid setting and are
+// otherwise ignored.
+//
+// Unlike Device, a BufferDevice is not safe for concurrent use.
+type BufferDevice struct {
+	// Tx holds all of the bytes written to the device so far.
+	Tx bytes.Buffer
+
+	rx []byte
+}
+
+var _ spi.Device = (*BufferDevice)(nil)
+
+// FromBuffers returns a BufferDevice that will return the bytes of rx in
+// response to reads. The device keeps its own copy of rx.
+func FromBuffers(rx []byte) *BufferDevice {
+	return &BufferDevice{
+		rx: append([]byte(nil), rx...),
+	}
+}
+
+func (d *BufferDevice) SetMode(mode spi.Mode) error {
+	return spi.ValidateMode(mode)
+}
+
+func (d *BufferDevice) SetBitOrder(order spi.BitOrder) error {
+	return nil
+}
+
+func (d *BufferDevice) SetMaxSpeedHz(speed uint32) error {
+	return spi.ValidateSpeed(speed)
+}
+
+func (d *BufferDevice) Write(data []byte) (int, error) {
+	d.Tx.Write(data)
+	return len(data), nil
+}
+
+func (d *BufferDevice) Read(data []byte) (int, error) {
+	d.read(data)
+	return len(data), nil
+}
+
+func (d *BufferDevice) Exchange(outData []byte, inData []byte) (int, error) {
+	if err := spi.ValidateExchange(outData, inData); err != nil {
+		return 0, err
+	}
+	d.Tx.Write(outData)
+	d.read(inData)
+	return len(inData), nil
+}
+
+func (d *BufferDevice) Request(outData []byte, inData []byte) (int, error) {
+	d.Tx.Write(outData)
+	d.read(inData)
+	return len(inData), nil
+}
+
+// read fills buf from the remaining rx bytes, zero-filling once they are
+// exhausted.
+func (d *BufferDevice) read(buf []byte) {
+	n := copy(buf, d.rx)
+	d.rx = d.rx[n:]
+	zero(buf[n:])
+}
@@ -0,0 +1,83 @@
+package testdevice
+
+import (
+	"sync"
+	"time"
+
+	"github.com/apparentlymart/go-spi/spi"
+)
+
+// Clock is a fake spi.Clock whose time advances only when the test calls
+// Advance, so that code that waits or times out can be tested
+// deterministically and without real delays.
+//
+// Calls to Sleep block, and channels returned by After receive, once the
+// clock has been advanced past the requested duration. A test typically
+// starts the code under test in another goroutine, uses Waiters to wait
+// until it is blocked on the clock, and then calls Advance.
+//
+// A Clock is safe for concurrent use.
+type Clock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []clockWaiter
+}
+
+type clockWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+var _ spi.Clock = (*Clock)(nil)
+
+// NewClock returns a Clock whose current time is start.
+func NewClock(start time.Time) *Clock {
+	return &Clock{now: start}
+}
+
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *Clock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, clockWaiter{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance moves the clock's time forward by d, waking any sleepers and
+// firing any channels from After whose time has then been reached.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	remain := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			remain = append(remain, w)
+			continue
+		}
+		w.ch <- c.now
+	}
+	c.waiters = remain
+}
+
+// Waiters returns the number of calls to Sleep, and channels returned by
+// After, that are still waiting for the clock to advance.
+func (c *Clock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
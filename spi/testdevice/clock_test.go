@@ -0,0 +1,64 @@
+package testdevice_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+func TestClock(t *testing.T) {
+	start := time.Unix(1000, 0)
+	clock := testdevice.NewClock(start)
+	if got := clock.Now(); !got.Equal(start) {
+		t.Fatalf("new clock reports %s; want %s", got, start)
+	}
+
+	select {
+	case <-clock.After(0):
+	default:
+		t.Errorf("After(0) did not fire immediately")
+	}
+
+	short := clock.After(time.Second)
+	long := clock.After(3 * time.Second)
+	if got := clock.Waiters(); got != 2 {
+		t.Fatalf("%d waiters; want 2", got)
+	}
+
+	clock.Advance(time.Second)
+	select {
+	case got := <-short:
+		if want := start.Add(time.Second); !got.Equal(want) {
+			t.Errorf("After(1s) received %s; want %s", got, want)
+		}
+	default:
+		t.Errorf("After(1s) did not fire after advancing 1s")
+	}
+	select {
+	case <-long:
+		t.Errorf("After(3s) fired after advancing only 1s")
+	default:
+	}
+	if got := clock.Waiters(); got != 1 {
+		t.Errorf("%d waiters after advancing; want 1", got)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		clock.Sleep(time.Second)
+		close(done)
+	}()
+	for clock.Waiters() != 2 {
+		time.Sleep(time.Millisecond)
+	}
+	clock.Advance(2 * time.Second)
+	<-done
+	<-long
+	if got := clock.Waiters(); got != 0 {
+		t.Errorf("%d waiters after all have fired; want 0", got)
+	}
+	if got, want := clock.Now(), start.Add(3*time.Second); !got.Equal(want) {
+		t.Errorf("clock reports %s; want %s", got, want)
+	}
+}
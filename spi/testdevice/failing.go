@@ -0,0 +1,122 @@
+package testdevice
+
+import (
+	"sync"
+
+	"github.com/apparentlymart/go-spi/spi"
+)
+
+// FailingDevice is an spi.Device that behaves like Device until a certain
+// number of calls have succeeded, and then returns a given error from
+// every subsequent call, for testing the error-handling paths of drivers.
+//
+// A failing transfer method reports that no bytes were transferred,
+// returning the error wrapped in a *spi.TransferError as spi.CheckTransfer
+// would, unless it is io.EOF or already a *spi.TransferError.
+//
+// By default only the transfer methods (Write, Read, Exchange and
+// Request) are counted and fail. Use Only to select specific methods
+// instead, including configuration methods such as SetMode.
+type FailingDevice struct {
+	*Device
+
+	mu        sync.Mutex
+	remaining int
+	err       error
+	only      map[string]bool
+}
+
+var _ spi.Device = (*FailingDevice)(nil)
+
+// FailAfter returns a FailingDevice that allows n calls to succeed and
+// then returns err from every call after that.
+func FailAfter(n int, err error) *FailingDevice {
+	return &FailingDevice{
+		Device:    New(),
+		remaining: n,
+		err:       err,
+	}
+}
+
+// Only restricts the device so that only calls to the named methods, such
+// as "Exchange" or "SetMode", are counted and fail. Calls to any other
+// method always succeed. It returns the receiver to allow chaining with
+// FailAfter.
+func (d *FailingDevice) Only(methods ...string) *FailingDevice {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.only = make(map[string]bool, len(methods))
+	for _, m := range methods {
+		d.only[m] = true
+	}
+	return d
+}
+
+func (d *FailingDevice) SetMode(mode spi.Mode) error {
+	if err := d.check("SetMode", false); err != nil {
+		return err
+	}
+	return d.Device.SetMode(mode)
+}
+
+func (d *FailingDevice) SetBitOrder(order spi.BitOrder) error {
+	if err := d.check("SetBitOrder", false); err != nil {
+		return err
+	}
+	return d.Device.SetBitOrder(order)
+}
+
+func (d *FailingDevice) SetMaxSpeedHz(speed uint32) error {
+	if err := d.check("SetMaxSpeedHz", false); err != nil {
+		return err
+	}
+	return d.Device.SetMaxSpeedHz(speed)
+}
+
+func (d *FailingDevice) Write(data []byte) (int, error) {
+	if err := d.check("Write", true); err != nil {
+		return spi.CheckTransfer("Write", 0, len(data), err)
+	}
+	return d.Device.Write(data)
+}
+
+func (d *FailingDevice) Read(data []byte) (int, error) {
+	if err := d.check("Read", true); err != nil {
+		return spi.CheckTransfer("Read", 0, len(data), err)
+	}
+	return d.Device.Read(data)
+}
+
+func (d *FailingDevice) Exchange(outData []byte, inData []byte) (int, error) {
+	if err := d.check("Exchange", true); err != nil {
+		return spi.CheckTransfer("Exchange", 0, len(inData), err)
+	}
+	return d.Device.Exchange(outData, inData)
+}
+
+func (d *FailingDevice) Request(outData []byte, inData []byte) (int, error) {
+	if err := d.check("Request", true); err != nil {
+		return spi.CheckTransfer("Request", 0, len(inData), err)
+	}
+	return d.Device.Request(outData, inData)
+}
+
+// check counts a call to the given method, returning the configured error
+// if the call should fail. transfer indicates whether the method is one
+// of the transfer methods that are counted by default.
+func (d *FailingDevice) check(method string, transfer bool) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.only != nil {
+		if !d.only[method] {
+			return nil
+		}
+	} else if !transfer {
+		return nil
+	}
+	if d.remaining > 0 {
+		d.remaining--
+		return nil
+	}
+	return d.err
+}
@@ -0,0 +1,81 @@
+package testdevice
+
+import (
+	"github.com/apparentlymart/go-spi/spi"
+)
+
+// LoopbackDevice is an spi.Device that behaves as if its MOSI line were
+// connected directly to its MISO line, so that Exchange returns exactly
+// the bytes that were written.
+//
+// For reads that are not part of a full-duplex exchange, such as Read and
+// the read phase of Request, the device returns its configured fill bytes.
+//
+// A LoopbackDevice is safe for concurrent use.
+type LoopbackDevice struct {
+	fill []byte
+}
+
+var _ spi.Device = (*LoopbackDevice)(nil)
+
+// Loopback returns a new LoopbackDevice. The given fill bytes are repeated
+// as necessary to fill buffers for reads that are not part of an exchange;
+// if no fill bytes are given, such reads produce zero bytes.
+func Loopback(fill ...byte) *LoopbackDevice {
+	return &LoopbackDevice{
+		fill: append([]byte(nil), fill...),
+	}
+}
+
+func (d *LoopbackDevice) SetMode(mode spi.Mode) error {
+	if err := spi.ValidateMode(mode); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (d *LoopbackDevice) SetBitOrder(order spi.BitOrder) error {
+	return nil
+}
+
+func (d *LoopbackDevice) SetMaxSpeedHz(speed uint32) error {
+	return spi.ValidateSpeed(speed)
+}
+
+// Write discards the given data, since nothing is read back during a
+// write.
+func (d *LoopbackDevice) Write(data []byte) (int, error) {
+	return len(data), nil
+}
+
+// Read fills data with the device's fill bytes.
+func (d *LoopbackDevice) Read(data []byte) (int, error) {
+	d.fillBuf(data)
+	return len(data), nil
+}
+
+// Exchange copies outData into inData. The two slices must have the same
+// length, or spi.ErrBufferLengthMismatch is returned.
+func (d *LoopbackDevice) Exchange(outData []byte, inData []byte) (int, error) {
+	if err := spi.ValidateExchange(outData, inData); err != nil {
+		return 0, err
+	}
+	copy(inData, outData)
+	return len(inData), nil
+}
+
+// Request discards outData and fills inData with the device's fill bytes.
+func (d *LoopbackDevice) Request(outData []byte, inData []byte) (int, error) {
+	d.fillBuf(inData)
+	return len(inData), nil
+}
+
+func (d *LoopbackDevice) fillBuf(buf []byte) {
+	if len(d.fill) == 0 {
+		zero(buf)
+		return
+	}
+	for i := range buf {
+		buf[i] = d.fill[i%len(d.fill)]
+	}
+}
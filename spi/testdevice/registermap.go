@@ -0,0 +1,158 @@
+package testdevice
+
+import (
+	"sync"
+
+	"github.com/apparentlymart/go-spi/spi"
+)
+
+// Register describes one register of the chip simulated by RegisterMap.
+type Register struct {
+	// Value is the register's initial value.
+	Value byte
+
+	// ReadOnly registers ignore writes, and WriteOnly registers always
+	// read as zero, as is typical of real chips.
+	ReadOnly  bool
+	WriteOnly bool
+}
+
+// RegisterMapDevice is an spi.Device that simulates a chip with a register
+// file, responding to the register command framing described by an
+// spi.RegisterConfig.
+//
+// Each Write, Exchange or Request is treated as a transaction whose first
+// byte written is a command byte addressing a register. For a write
+// command, the remaining bytes written are stored into the register. For
+// a read command, the bytes read after the command byte are taken from
+// the register. If the RegisterConfig enables auto-increment then
+// consecutive bytes address consecutive registers; if it also has an
+// AutoIncrementMask, auto-increment happens only for commands that
+// include the mask. Reads of registers not in the map return zero and
+// writes to them are ignored. Read, with no command byte, always returns
+// zeros.
+//
+// A RegisterMapDevice is safe for concurrent use.
+type RegisterMapDevice struct {
+	cfg spi.RegisterConfig
+
+	mu   sync.Mutex
+	regs map[byte]Register
+}
+
+var _ spi.Device = (*RegisterMapDevice)(nil)
+
+// RegisterMap returns a RegisterMapDevice simulating a chip with the given
+// registers, using the given command framing.
+func RegisterMap(regs map[byte]Register, cfg spi.RegisterConfig) *RegisterMapDevice {
+	copied := make(map[byte]Register, len(regs))
+	for addr, reg := range regs {
+		copied[addr] = reg
+	}
+	return &RegisterMapDevice{
+		cfg:  cfg,
+		regs: copied,
+	}
+}
+
+// Value returns the current value of the register at the given address,
+// regardless of whether it is write-only, or zero if there is no such
+// register.
+func (d *RegisterMapDevice) Value(addr byte) byte {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.regs[addr].Value
+}
+
+func (d *RegisterMapDevice) SetMode(mode spi.Mode) error {
+	return spi.ValidateMode(mode)
+}
+
+func (d *RegisterMapDevice) SetBitOrder(order spi.BitOrder) error {
+	return nil
+}
+
+func (d *RegisterMapDevice) SetMaxSpeedHz(speed uint32) error {
+	return spi.ValidateSpeed(speed)
+}
+
+func (d *RegisterMapDevice) Write(data []byte) (int, error) {
+	if len(data) > 0 {
+		d.transact(data[0], data[1:], nil)
+	}
+	return len(data), nil
+}
+
+func (d *RegisterMapDevice) Read(data []byte) (int, error) {
+	zero(data)
+	return len(data), nil
+}
+
+// Exchange treats outData[0] as the command byte. The byte read during the
+// command byte is zero, and the remaining bytes are exchanged with the
+// addressed registers.
+func (d *RegisterMapDevice) Exchange(outData []byte, inData []byte) (int, error) {
+	if err := spi.ValidateExchange(outData, inData); err != nil {
+		return 0, err
+	}
+	if len(outData) > 0 {
+		inData[0] = 0
+		d.transact(outData[0], outData[1:], inData[1:])
+	}
+	return len(inData), nil
+}
+
+// Request treats outData[0] as the command byte. For read commands inData
+// is filled from the addressed registers, while for write commands the
+// remaining bytes of outData are stored and inData is zero-filled.
+func (d *RegisterMapDevice) Request(outData []byte, inData []byte) (int, error) {
+	if len(outData) > 0 {
+		d.transact(outData[0], outData[1:], inData)
+	} else {
+		zero(inData)
+	}
+	return len(inData), nil
+}
+
+// transact performs a transaction beginning with the given command byte,
+// storing data into registers for a write command or filling in from
+// registers for a read command. Whichever of data or in is unused by the
+// command is ignored or zero-filled, respectively.
+func (d *RegisterMapDevice) transact(cmd byte, data, in []byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	addr, read := d.cfg.DecodeCommand(cmd)
+	step := byte(0)
+	if d.cfg.AutoIncrement && (d.cfg.AutoIncrementMask == 0 || cmd&d.cfg.AutoIncrementMask != 0) {
+		step = 1
+	}
+
+	if read {
+		for i := range in {
+			reg := d.regs[addr]
+			if reg.WriteOnly {
+				in[i] = 0
+			} else {
+				in[i] = reg.Value
+			}
+			addr += step
+		}
+		return
+	}
+
+	zero(in)
+	for _, b := range data {
+		if reg, ok := d.regs[addr]; ok && !reg.ReadOnly {
+			reg.Value = b
+			d.regs[addr] = reg
+		}
+		addr += step
+	}
+}
+
+func zero(buf []byte) {
+	for i := range buf {
+		buf[i] = 0
+	}
+}
@@ -0,0 +1,147 @@
+package testdevice
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+)
+
+// Step is one step of the script given to Scripted: a transfer in which
+// the driver is expected to write exactly the bytes in Write, and will
+// then be given the bytes in Read.
+type Step struct {
+	Write []byte
+	Read  []byte
+}
+
+// ScriptedDevice is an spi.Device that checks each transfer made by a
+// driver against a script, reporting any deviation as a test failure.
+//
+// Each call to Write, Read, Exchange or Request consumes one step of the
+// script. The bytes written must exactly match the step's Write bytes,
+// and the number of bytes the call reads must exactly match the length of
+// the step's Read bytes, which are then returned. A Write call must
+// therefore correspond to a step with no Read bytes, and a Read call to a
+// step with no Write bytes. Configuration calls are not part of the
+// script and succeed for any valid setting.
+//
+// When a call deviates from the script the failure is reported via the
+// testing.TB with the index of the step, and the call returns a
+// *spi.TransferError describing it.
+type ScriptedDevice struct {
+	t testing.TB
+
+	mu    sync.Mutex
+	steps []Step
+	next  int
+}
+
+var _ spi.Device = (*ScriptedDevice)(nil)
+
+// Scripted returns a ScriptedDevice that reports failures to t and expects
+// the given steps in order.
+func Scripted(t testing.TB, steps ...Step) *ScriptedDevice {
+	return &ScriptedDevice{
+		t:     t,
+		steps: steps,
+	}
+}
+
+// Done reports a test failure if any steps of the script have not yet been
+// performed. Tests should typically call it once the driver has finished.
+func (d *ScriptedDevice) Done() {
+	d.t.Helper()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.next < len(d.steps) {
+		d.t.Errorf("script ended early: %d of %d steps were not performed", len(d.steps)-d.next, len(d.steps))
+	}
+}
+
+func (d *ScriptedDevice) SetMode(mode spi.Mode) error {
+	if err := spi.ValidateMode(mode); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (d *ScriptedDevice) SetBitOrder(order spi.BitOrder) error {
+	return nil
+}
+
+func (d *ScriptedDevice) SetMaxSpeedHz(speed uint32) error {
+	return spi.ValidateSpeed(speed)
+}
+
+func (d *ScriptedDevice) Write(data []byte) (int, error) {
+	d.t.Helper()
+	if err := d.step("Write", data, nil); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+func (d *ScriptedDevice) Read(data []byte) (int, error) {
+	d.t.Helper()
+	if err := d.step("Read", nil, data); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+func (d *ScriptedDevice) Exchange(outData []byte, inData []byte) (int, error) {
+	d.t.Helper()
+	if err := spi.ValidateExchange(outData, inData); err != nil {
+		return 0, err
+	}
+	if err := d.step("Exchange", outData, inData); err != nil {
+		return 0, err
+	}
+	return len(inData), nil
+}
+
+func (d *ScriptedDevice) Request(outData []byte, inData []byte) (int, error) {
+	d.t.Helper()
+	if err := d.step("Request", outData, inData); err != nil {
+		return 0, err
+	}
+	return len(inData), nil
+}
+
+// step checks a transfer that wrote out and wants to read into in against
+// the next step of the script, filling in from the step on success.
+func (d *ScriptedDevice) step(op string, out, in []byte) error {
+	d.t.Helper()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	idx := d.next
+	if idx >= len(d.steps) {
+		err := fmt.Errorf("unexpected %s after end of script (%d steps)", op, len(d.steps))
+		return d.fail(op, err)
+	}
+	d.next++
+	want := d.steps[idx]
+
+	if !bytes.Equal(out, want.Write) {
+		err := fmt.Errorf("step %d: %s wrote [% x], but script expects [% x]", idx, op, out, want.Write)
+		return d.fail(op, err)
+	}
+	if len(in) != len(want.Read) {
+		err := fmt.Errorf("step %d: %s reads %d bytes, but script provides %d", idx, op, len(in), len(want.Read))
+		return d.fail(op, err)
+	}
+	copy(in, want.Read)
+	return nil
+}
+
+// fail reports a deviation from the script and returns it as the error
+// from a transfer that moved no bytes.
+func (d *ScriptedDevice) fail(op string, err error) error {
+	d.t.Helper()
+	d.t.Error(err)
+	return &spi.TransferError{Op: op, Err: err}
+}
@@ -0,0 +1,168 @@
+// Package testdevice provides in-memory implementations of the interfaces
+// in package spi, so that device drivers can be tested without real
+// hardware.
+package testdevice
+
+import (
+	"sync"
+
+	"github.com/apparentlymart/go-spi/spi"
+)
+
+// Device is an in-memory spi.Device that records everything written to it
+// and returns caller-provided canned responses when read.
+//
+// Bytes written by Write, Exchange and Request are all appended to a
+// single log that can be retrieved with Written. Bytes read by Read,
+// Exchange and Request are all taken from a single queue of responses
+// that is filled by Respond; once the queue is exhausted, reads produce
+// zero bytes.
+//
+// A Device is safe for concurrent use.
+type Device struct {
+	mu        sync.Mutex
+	written   []byte
+	responses []byte
+
+	mode     spi.Mode
+	bitOrder spi.BitOrder
+	speedHz  uint32
+	duplex   spi.DuplexMode
+}
+
+var _ spi.Device = (*Device)(nil)
+var _ spi.SpeedInspector = (*Device)(nil)
+var _ spi.BitOrderInspector = (*Device)(nil)
+var _ spi.ModeInspector = (*Device)(nil)
+var _ spi.DuplexConfigurator = (*Device)(nil)
+
+// New returns a new Device with nothing written and no queued responses.
+func New() *Device {
+	return &Device{}
+}
+
+// Respond appends the given bytes to the queue of data that will be
+// returned by subsequent reads.
+func (d *Device) Respond(data []byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.responses = append(d.responses, data...)
+}
+
+// Written returns a copy of all of the bytes written to the device so far.
+func (d *Device) Written() []byte {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]byte(nil), d.written...)
+}
+
+func (d *Device) SetMode(mode spi.Mode) error {
+	if err := spi.ValidateMode(mode); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.mode = mode
+	return nil
+}
+
+func (d *Device) SetBitOrder(order spi.BitOrder) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.bitOrder = order
+	return nil
+}
+
+func (d *Device) SetMaxSpeedHz(speed uint32) error {
+	if err := spi.ValidateSpeed(speed); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.speedHz = speed
+	return nil
+}
+
+func (d *Device) Write(data []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.written = append(d.written, data...)
+	return len(data), nil
+}
+
+func (d *Device) Read(data []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.respond(data)
+	return len(data), nil
+}
+
+// Exchange records outData as written and fills inData from the queued
+// responses. The two slices must have the same length, and the device
+// must not have been set to spi.HalfDuplex.
+func (d *Device) Exchange(outData []byte, inData []byte) (int, error) {
+	if err := spi.ValidateExchange(outData, inData); err != nil {
+		return 0, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.duplex == spi.HalfDuplex {
+		return 0, spi.ErrHalfDuplex
+	}
+	d.written = append(d.written, outData...)
+	d.respond(inData)
+	return len(inData), nil
+}
+
+// Request records outData as written and then fills inData from the queued
+// responses. The returned count is the number of bytes read into inData.
+func (d *Device) Request(outData []byte, inData []byte) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.written = append(d.written, outData...)
+	d.respond(inData)
+	return len(inData), nil
+}
+
+// SetDuplex implements spi.DuplexConfigurator.
+func (d *Device) SetDuplex(mode spi.DuplexMode) error {
+	if mode != spi.FullDuplex && mode != spi.HalfDuplex {
+		return spi.NotSupported(mode.String())
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.duplex = mode
+	return nil
+}
+
+// respond fills buf from the response queue, zero-filling once the queue
+// is exhausted. The caller must hold d.mu.
+func (d *Device) respond(buf []byte) {
+	n := copy(buf, d.responses)
+	d.responses = d.responses[n:]
+	zero(buf[n:])
+}
+
+// MaxSpeedHz returns the speed most recently passed to SetMaxSpeedHz,
+// implementing spi.SpeedInspector.
+func (d *Device) MaxSpeedHz() (uint32, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.speedHz, nil
+}
+
+// BitOrder returns the bit order most recently passed to SetBitOrder,
+// implementing spi.BitOrderInspector.
+func (d *Device) BitOrder() (spi.BitOrder, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.bitOrder, nil
+}
+
+// Mode returns the mode most recently passed to SetMode, implementing
+// spi.ModeInspector.
+func (d *Device) Mode() (spi.Mode, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.mode, nil
+}
@@ -0,0 +1,71 @@
+package spi
+
+import (
+	"time"
+)
+
+// NewThrottled returns a Device that passes all calls through to d, but
+// delays the return of each transfer so that overall it moves no more
+// than bytesPerSec bytes per second, for simulating a slow bus in tests
+// such as those for timeout and cancellation handling.
+//
+// The delay is measured from the start of each transfer, so any time the
+// underlying transfer takes counts towards it. A Request counts both the
+// bytes written and the bytes read. The throttle only adds latency; it
+// does not emulate the timing of a real bus, where the data moves
+// progressively during the transfer rather than all at once.
+//
+// NewThrottled panics if bytesPerSec is not positive.
+func NewThrottled(d Device, bytesPerSec int) Device {
+	return NewThrottledClock(d, bytesPerSec, SystemClock)
+}
+
+// NewThrottledClock is like NewThrottled, but measures and waits using the
+// given clock.
+func NewThrottledClock(d Device, bytesPerSec int, clock Clock) Device {
+	if bytesPerSec <= 0 {
+		panic("NewThrottled requires a positive bytesPerSec")
+	}
+	return throttled{
+		ForwardingDevice: ForwardingDevice{Device: d},
+		bytesPerSec:      bytesPerSec,
+		clock:            clock,
+	}
+}
+
+type throttled struct {
+	ForwardingDevice
+	bytesPerSec int
+	clock       Clock
+}
+
+func (d throttled) Write(data []byte) (int, error) {
+	defer d.throttle(len(data), d.clock.Now())
+	return d.Device.Write(data)
+}
+
+func (d throttled) Read(data []byte) (int, error) {
+	defer d.throttle(len(data), d.clock.Now())
+	return d.Device.Read(data)
+}
+
+func (d throttled) Exchange(outData []byte, inData []byte) (int, error) {
+	defer d.throttle(len(outData), d.clock.Now())
+	return d.Device.Exchange(outData, inData)
+}
+
+func (d throttled) Request(outData []byte, inData []byte) (int, error) {
+	defer d.throttle(len(outData)+len(inData), d.clock.Now())
+	return d.Device.Request(outData, inData)
+}
+
+// throttle sleeps until enough time has passed since start for the given
+// number of bytes to have been transferred at the configured rate.
+func (d throttled) throttle(byteCount int, start time.Time) {
+	// Whole seconds and the remainder are computed separately so that
+	// large transfers cannot overflow time.Duration's nanosecond count.
+	secs := byteCount / d.bytesPerSec
+	rem := byteCount % d.bytesPerSec
+	delay := time.Duration(secs)*time.Second + time.Duration(rem)*time.Second/time.Duration(d.bytesPerSec)
+	d.clock.Sleep(start.Add(delay).Sub(d.clock.Now()))
+}
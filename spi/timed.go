@@ -0,0 +1,82 @@
+package spi
+
+import (
+	"time"
+)
+
+// NewTimed returns a Device that passes all calls through to d and, after
+// each one, calls sink with the name of the method, such as "Exchange",
+// and the time the call took.
+//
+// sink is called whether or not the operation succeeds, so that slow
+// failures are visible too. It is called synchronously, so it should
+// return quickly.
+func NewTimed(d Device, sink func(op string, d time.Duration)) Device {
+	return timed{
+		ForwardingDevice: ForwardingDevice{Device: d},
+		sink:             sink,
+	}
+}
+
+type timed struct {
+	ForwardingDevice
+	sink func(op string, d time.Duration)
+}
+
+func (d timed) SetMode(mode Mode) error {
+	defer d.measure("SetMode", time.Now())
+	return d.Device.SetMode(mode)
+}
+
+func (d timed) SetBitOrder(order BitOrder) error {
+	defer d.measure("SetBitOrder", time.Now())
+	return d.Device.SetBitOrder(order)
+}
+
+func (d timed) SetMaxSpeedHz(speed uint32) error {
+	defer d.measure("SetMaxSpeedHz", time.Now())
+	return d.Device.SetMaxSpeedHz(speed)
+}
+
+func (d timed) Write(data []byte) (int, error) {
+	defer d.measure("Write", time.Now())
+	return d.Device.Write(data)
+}
+
+func (d timed) Read(data []byte) (int, error) {
+	defer d.measure("Read", time.Now())
+	return d.Device.Read(data)
+}
+
+func (d timed) Exchange(outData []byte, inData []byte) (int, error) {
+	defer d.measure("Exchange", time.Now())
+	return d.Device.Exchange(outData, inData)
+}
+
+func (d timed) Request(outData []byte, inData []byte) (int, error) {
+	defer d.measure("Request", time.Now())
+	return d.Device.Request(outData, inData)
+}
+
+func (d timed) measure(op string, start time.Time) {
+	d.sink(op, time.Since(start))
+}
+
+// NewSlowLog returns a Device that passes all calls through to d and calls
+// log with the name of the method and the time taken for any transfer,
+// such as an Exchange, that takes longer than threshold, for diagnosing
+// intermittent bus slowdowns without logging every transfer.
+//
+// Slow transfers are logged whether or not they succeed. Configuration
+// calls are never logged. It is built on NewTimed, so log is called
+// synchronously in the same way as NewTimed's sink.
+func NewSlowLog(d Device, threshold time.Duration, log func(op string, d time.Duration)) Device {
+	return NewTimed(d, func(op string, elapsed time.Duration) {
+		switch op {
+		case "Write", "Read", "Exchange", "Request":
+			if elapsed > threshold {
+				log(op, elapsed)
+			}
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package spi
+
+import (
+	"time"
+)
+
+// WithTimeout returns a Device that returns ErrTimeout from any transfer
+// on d that does not complete within the given timeout, as a safety valve
+// against a wedged bus on backends that cannot cancel transfers.
+//
+// Each transfer runs in its own goroutine. Since the underlying transfer
+// generally cannot be interrupted, that goroutine continues running after
+// a timeout until the real transfer completes, and may continue to write
+// into the caller's buffers until then. The wrapper never starts a new
+// transfer on d while an earlier one is still running, so a subsequent
+// call waits for the earlier transfer to finish, within its own timeout.
+//
+// Configuration calls are passed through to d without a timeout.
+func WithTimeout(d Device, timeout time.Duration) Device {
+	return WithTimeoutClock(d, timeout, SystemClock)
+}
+
+// WithTimeoutClock is like WithTimeout, but measures the timeout using the
+// given clock.
+func WithTimeoutClock(d Device, timeout time.Duration, clock Clock) Device {
+	idle := make(chan struct{}, 1)
+	idle <- struct{}{}
+	return &timeoutDevice{
+		ForwardingDevice: ForwardingDevice{Device: d},
+		timeout:          timeout,
+		clock:            clock,
+		idle:             idle,
+	}
+}
+
+type timeoutDevice struct {
+	ForwardingDevice
+	timeout time.Duration
+	clock   Clock
+
+	// idle holds a token whenever no transfer is running on the
+	// underlying device.
+	idle chan struct{}
+}
+
+func (d *timeoutDevice) Write(data []byte) (int, error) {
+	return d.run(func() (int, error) {
+		return d.Device.Write(data)
+	})
+}
+
+func (d *timeoutDevice) Read(data []byte) (int, error) {
+	return d.run(func() (int, error) {
+		return d.Device.Read(data)
+	})
+}
+
+func (d *timeoutDevice) Exchange(outData []byte, inData []byte) (int, error) {
+	return d.run(func() (int, error) {
+		return d.Device.Exchange(outData, inData)
+	})
+}
+
+func (d *timeoutDevice) Request(outData []byte, inData []byte) (int, error) {
+	return d.run(func() (int, error) {
+		return d.Device.Request(outData, inData)
+	})
+}
+
+func (d *timeoutDevice) run(transfer func() (int, error)) (int, error) {
+	expired := d.clock.After(d.timeout)
+
+	select {
+	case <-d.idle:
+	case <-expired:
+		return 0, ErrTimeout
+	}
+
+	type result struct {
+		n   int
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		n, err := transfer()
+		d.idle <- struct{}{}
+		done <- result{n, err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.n, r.err
+	case <-expired:
+		return 0, ErrTimeout
+	}
+}
+
+// DeadlineDevice is an optional interface implemented by devices that
+// support net.Conn-style deadlines, bounding the time taken by their Read
+// and Write methods.
+//
+// A deadline is an absolute time after which a blocked or future call
+// fails with a timeout error, rather than a per-call duration. A zero
+// value for t means that calls will not time out. Backends over a file
+// descriptor can typically implement this using the file's own deadline
+// support; implementations that cannot honor deadlines return
+// ErrNotSupported.
+type DeadlineDevice interface {
+	SetReadDeadline(t time.Time) error
+	SetWriteDeadline(t time.Time) error
+}
@@ -0,0 +1,69 @@
+package spi_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+// blockingDevice is a device whose writes block until release is closed.
+type blockingDevice struct {
+	*testdevice.Device
+	release chan struct{}
+}
+
+func (d blockingDevice) Write(data []byte) (int, error) {
+	<-d.release
+	return d.Device.Write(data)
+}
+
+// waitForWaiters waits until n callers are blocked on the given clock.
+func waitForWaiters(t *testing.T, clock *testdevice.Clock, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for clock.Waiters() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d callers waiting on the clock; want %d", clock.Waiters(), n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	clock := testdevice.NewClock(time.Unix(0, 0))
+	bd := blockingDevice{testdevice.New(), make(chan struct{})}
+	d := spi.WithTimeoutClock(bd, time.Second, clock)
+
+	errs := make(chan error)
+	write := func() {
+		_, err := d.Write([]byte{0x01})
+		errs <- err
+	}
+
+	go write()
+	waitForWaiters(t, clock, 1)
+	clock.Advance(time.Second)
+	if err := <-errs; err != spi.ErrTimeout {
+		t.Errorf("stalled write returned %v; want ErrTimeout", err)
+	}
+
+	// The first write is still running, so this one must wait for it.
+	go write()
+	waitForWaiters(t, clock, 1)
+	clock.Advance(time.Second)
+	if err := <-errs; err != spi.ErrTimeout {
+		t.Errorf("write behind a stalled write returned %v; want ErrTimeout", err)
+	}
+
+	close(bd.release)
+	go write()
+	if err := <-errs; err != nil {
+		t.Errorf("write after the stall cleared returned %v", err)
+	}
+	// Only the first and last writes reached the device.
+	if got := len(bd.Written()); got != 2 {
+		t.Errorf("device received %d bytes; want 2", got)
+	}
+}
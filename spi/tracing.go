@@ -0,0 +1,70 @@
+package spi
+
+// Tracer receives notifications of transfers on a device created by
+// NewTraced, for integrating with tracing systems such as those that
+// record spans.
+//
+// The hooks receive the caller's actual buffers rather than copies, so
+// that a tracer can choose to redact or hash sensitive payloads without
+// the cost of copying them. A tracer must not modify the buffers or retain
+// them after the hook returns.
+type Tracer interface {
+	// BeginTransfer is called before a transfer starts, with the name of
+	// the method, such as "Exchange", and the data to be written, which
+	// is nil for Read.
+	BeginTransfer(op string, out []byte)
+
+	// EndTransfer is called when the transfer announced by the
+	// corresponding BeginTransfer call completes, with its results and
+	// the n bytes of data read, which is nil for Write.
+	EndTransfer(n int, in []byte, err error)
+}
+
+// NewTraced returns a Device that passes all calls through to d, reporting
+// each transfer to t. If t is nil then d is returned unchanged.
+//
+// Configuration calls are passed through without tracing. If the device
+// is used concurrently then the calls to t will likewise be concurrent,
+// and hooks for different transfers may interleave.
+func NewTraced(d Device, t Tracer) Device {
+	if t == nil {
+		return d
+	}
+	return traced{
+		ForwardingDevice: ForwardingDevice{Device: d},
+		tracer:           t,
+	}
+}
+
+type traced struct {
+	ForwardingDevice
+	tracer Tracer
+}
+
+func (d traced) Write(data []byte) (int, error) {
+	d.tracer.BeginTransfer("Write", data)
+	n, err := d.Device.Write(data)
+	d.tracer.EndTransfer(n, nil, err)
+	return n, err
+}
+
+func (d traced) Read(data []byte) (int, error) {
+	d.tracer.BeginTransfer("Read", nil)
+	n, err := d.Device.Read(data)
+	d.tracer.EndTransfer(n, received(data, n), err)
+	return n, err
+}
+
+func (d traced) Exchange(outData []byte, inData []byte) (int, error) {
+	d.tracer.BeginTransfer("Exchange", outData)
+	n, err := d.Device.Exchange(outData, inData)
+	d.tracer.EndTransfer(n, received(inData, n), err)
+	return n, err
+}
+
+func (d traced) Request(outData []byte, inData []byte) (int, error) {
+	d.tracer.BeginTransfer("Request", outData)
+	n, err := d.Device.Request(outData, inData)
+	d.tracer.EndTransfer(n, received(inData, n), err)
+	return n, err
+}
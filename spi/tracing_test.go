@@ -0,0 +1,32 @@
+package spi_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+// lastTrace is a Tracer that keeps a copy of the data most recently read.
+type lastTrace struct {
+	in []byte
+}
+
+func (t *lastTrace) BeginTransfer(op string, out []byte) {}
+
+func (t *lastTrace) EndTransfer(n int, in []byte, err error) {
+	t.in = append([]byte(nil), in...)
+}
+
+func TestTracedShortExchange(t *testing.T) {
+	td := testdevice.New()
+	td.Respond([]byte{0xaa, 0xbb})
+	tr := &lastTrace{}
+	d := spi.NewTraced(shortDevice{td}, tr)
+
+	d.Exchange([]byte{0x01, 0x02}, make([]byte, 2))
+	if got, want := tr.in, []byte{0xaa}; !bytes.Equal(got, want) {
+		t.Errorf("traced [% x] as read; want [% x]", got, want)
+	}
+}
@@ -0,0 +1,138 @@
+package spi
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Transaction is a sequence of transfers that are performed together while
+// the device remains selected, for devices that need a command followed
+// by several separate reads or writes under one chip-select assertion.
+//
+// Transfers are added to a transaction with Write, Read and Exchange, and
+// are then all performed in order when Close is called. Buffers given for
+// reading are therefore not filled until Close returns, and the caller
+// must not modify any of the given buffers until then.
+type Transaction struct {
+	dev      Device
+	segments []Segment
+	closed   bool
+}
+
+// Begin starts a new transaction on the given device.
+//
+// Transactions require a device that implements BatchDevice, in which case
+// they are submitted as a single batch, or ChipSelectController, in which
+// case chip-select is asserted manually around the transfers. For other
+// devices, Close returns an error wrapping ErrNotSupported.
+func Begin(d Device) *Transaction {
+	return &Transaction{dev: d}
+}
+
+// Write adds a write of the given data to the transaction.
+func (t *Transaction) Write(data []byte) {
+	t.segments = append(t.segments, Segment{Out: data})
+}
+
+// Read adds a read into the given buffer to the transaction.
+func (t *Transaction) Read(data []byte) {
+	t.segments = append(t.segments, Segment{In: data})
+}
+
+// Exchange adds a full-duplex exchange to the transaction. The two slices
+// must have the same length.
+func (t *Transaction) Exchange(outData []byte, inData []byte) {
+	t.segments = append(t.segments, Segment{Out: outData, In: inData})
+}
+
+// Close performs all of the transaction's transfers under a single
+// chip-select assertion, returning the first error encountered. A
+// transaction cannot be used again after it is closed.
+func (t *Transaction) Close() error {
+	if t.closed {
+		return errors.New("transaction is already closed")
+	}
+	t.closed = true
+	if len(t.segments) == 0 {
+		return nil
+	}
+	for _, seg := range t.segments {
+		if seg.Out != nil && seg.In != nil {
+			if err := ValidateExchange(seg.Out, seg.In); err != nil {
+				return err
+			}
+		}
+	}
+
+	if bd, ok := t.dev.(BatchDevice); ok {
+		for i := range t.segments[:len(t.segments)-1] {
+			t.segments[i].KeepCS = true
+		}
+		return bd.Transfer(t.segments)
+	}
+
+	csc, ok := t.dev.(ChipSelectController)
+	if !ok || !Capabilities(t.dev).ChipSelectController {
+		return NotSupported("transaction")
+	}
+	if err := csc.AssertCS(); err != nil {
+		return err
+	}
+	err := t.perform()
+	if derr := csc.DeassertCS(); err == nil {
+		err = derr
+	}
+	return err
+}
+
+// perform runs each of the segments in turn on the device, assuming that
+// the caller is holding chip-select asserted.
+func (t *Transaction) perform() error {
+	for _, seg := range t.segments {
+		if err := transferSegment(t.dev, seg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadWriteRequester is an optional interface implemented by devices that
+// can natively perform a three-phase transaction: writing a command,
+// reading a response, and then writing an acknowledgement, all under a
+// single chip-select assertion, as required by some secure elements.
+//
+// RequestReadWrite returns a newly allocated slice of length readLen
+// holding the response. The cmd and ack slices are only read, and are
+// not retained after the call returns. Implementations that cannot hold
+// chip-select across all three phases return an error wrapping
+// ErrNotSupported.
+type ReadWriteRequester interface {
+	RequestReadWrite(cmd []byte, readLen int, ack []byte) ([]byte, error)
+}
+
+// RequestReadWrite performs a three-phase write-read-write transaction on
+// the given device, with the same meaning as
+// ReadWriteRequester.RequestReadWrite.
+//
+// If d implements ReadWriteRequester then its native support is used.
+// Otherwise the phases are performed as a Transaction, and so the result
+// is an error wrapping ErrNotSupported if d cannot hold chip-select in
+// that way either. It returns an error without using d if readLen is
+// negative.
+func RequestReadWrite(d Device, cmd []byte, readLen int, ack []byte) ([]byte, error) {
+	if readLen < 0 {
+		return nil, fmt.Errorf("invalid read length %d", readLen)
+	}
+	if rw, ok := d.(ReadWriteRequester); ok {
+		return rw.RequestReadWrite(cmd, readLen, ack)
+	}
+	resp := make([]byte, readLen)
+	t := Begin(d)
+	t.Write(cmd)
+	t.Read(resp)
+	t.Write(ack)
+	if err := t.Close(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,18 @@
+package spi_test
+
+import (
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+	"github.com/apparentlymart/go-spi/spi/testdevice"
+)
+
+func TestRequestReadWriteNegativeLength(t *testing.T) {
+	d := testdevice.New()
+	if _, err := spi.RequestReadWrite(d, []byte{0x01}, -1, []byte{0x02}); err == nil {
+		t.Errorf("no error for negative read length")
+	}
+	if len(d.Written()) != 0 {
+		t.Errorf("wrote to the device despite the invalid length")
+	}
+}
@@ -0,0 +1,58 @@
+package spi
+
+import (
+	"reflect"
+)
+
+// Unwrapper is implemented by devices that wrap another device, such as
+// those returned by NewSynchronized or NewRecordingDevice, in a similar
+// way to how wrapped errors implement an Unwrap method.
+//
+// Unwrap returns the device that the wrapper passes its calls through to.
+// Calling methods of the result directly bypasses the wrapper's own
+// behavior, so it is primarily for inspection. Every wrapper in this
+// package around a single device implements Unwrapper, and wrappers
+// defined elsewhere should do the same so that SameDevice can see through
+// them. The result of Combine does not, since it has two underlying
+// devices rather than one.
+type Unwrapper interface {
+	Unwrap() Device
+}
+
+// SameDevice returns true if a and b are, or are wrappers around, the same
+// underlying device, such as when two drivers have been handed the same
+// bus and must coordinate their use of it.
+//
+// It unwraps each of the given devices as far as possible using
+// Unwrapper and then compares the innermost devices. Devices whose
+// dynamic types are not comparable are never considered the same unless
+// both are nil.
+func SameDevice(a, b Device) bool {
+	a, b = Base(a), Base(b)
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.TypeOf(a).Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// Base returns the innermost device that d wraps, by calling Unwrap
+// repeatedly for as long as the result implements Unwrapper, or d itself
+// if it does not wrap another device. This is useful for debugging and
+// for inspecting the capabilities of the backend behind a chain of
+// wrappers.
+func Base(d Device) Device {
+	for {
+		u, ok := d.(Unwrapper)
+		if !ok {
+			return d
+		}
+		inner := u.Unwrap()
+		if inner == nil {
+			return d
+		}
+		d = inner
+	}
+}
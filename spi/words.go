@@ -0,0 +1,115 @@
+package spi
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// ReadUint16BE sends the given command bytes and then reads a 16-bit
+// big-endian value, as a single Request.
+func ReadUint16BE(d Device, cmd []byte) (uint16, error) {
+	return ReadRegAs[uint16](d, cmd, binary.BigEndian)
+}
+
+// ReadUint16LE sends the given command bytes and then reads a 16-bit
+// little-endian value, as a single Request.
+func ReadUint16LE(d Device, cmd []byte) (uint16, error) {
+	return ReadRegAs[uint16](d, cmd, binary.LittleEndian)
+}
+
+// WriteUint16BE writes the given command bytes followed by v encoded as a
+// 16-bit big-endian value, as a single transfer.
+func WriteUint16BE(d WritableDevice, cmd []byte, v uint16) error {
+	return WriteRegAs(d, cmd, v, binary.BigEndian)
+}
+
+// WriteUint16LE writes the given command bytes followed by v encoded as a
+// 16-bit little-endian value, as a single transfer.
+func WriteUint16LE(d WritableDevice, cmd []byte, v uint16) error {
+	return WriteRegAs(d, cmd, v, binary.LittleEndian)
+}
+
+// Integer is a constraint matching the fixed-width integer types that can
+// be read and written by ReadRegAs and WriteRegAs.
+//
+// int, uint and uintptr are excluded because their width varies between
+// platforms, whereas a register's width is fixed by the chip.
+type Integer interface {
+	~int8 | ~int16 | ~int32 | ~int64 | ~uint8 | ~uint16 | ~uint32 | ~uint64
+}
+
+// ReadRegAs sends the given command bytes and then reads a value of type
+// T, as a single Request, decoding it using the given byte order. The
+// number of bytes read is the width of T.
+func ReadRegAs[T Integer](d Device, cmd []byte, order binary.ByteOrder) (T, error) {
+	var v T
+	size := binary.Size(v)
+	buf := make([]byte, size)
+	n, err := d.Request(cmd, buf)
+	if err != nil {
+		return 0, err
+	}
+	if n < size {
+		return 0, fmt.Errorf("read %d bytes, but a %T needs %d: %w", n, v, size, io.ErrUnexpectedEOF)
+	}
+	switch size {
+	case 1:
+		v = T(buf[0])
+	case 2:
+		v = T(order.Uint16(buf))
+	case 4:
+		v = T(order.Uint32(buf))
+	default:
+		v = T(order.Uint64(buf))
+	}
+	return v, nil
+}
+
+// WriteRegAs writes the given command bytes followed by v encoded using
+// the given byte order, as a single transfer. The number of bytes written
+// after the command is the width of T.
+func WriteRegAs[T Integer](d WritableDevice, cmd []byte, v T, order binary.ByteOrder) error {
+	size := binary.Size(v)
+	frame := make([]byte, len(cmd)+size)
+	copy(frame, cmd)
+	buf := frame[len(cmd):]
+	switch size {
+	case 1:
+		buf[0] = byte(v)
+	case 2:
+		order.PutUint16(buf, uint16(v))
+	case 4:
+		order.PutUint32(buf, uint32(v))
+	default:
+		order.PutUint64(buf, uint64(v))
+	}
+	return writeOnce(d, frame)
+}
+
+// SwapBytes16 reverses the byte order of each 16-bit word in buf in place,
+// for data from hardware that delivers words in the opposite byte order
+// to the one the driver expects and cannot be configured to reorder them
+// using WordSizeConfigurator.
+//
+// SwapBytes16 panics if the length of buf is not a multiple of two.
+func SwapBytes16(buf []byte) {
+	if len(buf)%2 != 0 {
+		panic(fmt.Sprintf("SwapBytes16 buffer length %d is not a multiple of 2", len(buf)))
+	}
+	for i := 0; i < len(buf); i += 2 {
+		buf[i], buf[i+1] = buf[i+1], buf[i]
+	}
+}
+
+// SwapBytes32 is like SwapBytes16 but for 32-bit words.
+//
+// SwapBytes32 panics if the length of buf is not a multiple of four.
+func SwapBytes32(buf []byte) {
+	if len(buf)%4 != 0 {
+		panic(fmt.Sprintf("SwapBytes32 buffer length %d is not a multiple of 4", len(buf)))
+	}
+	for i := 0; i < len(buf); i += 4 {
+		buf[i], buf[i+1], buf[i+2], buf[i+3] = buf[i+3], buf[i+2], buf[i+1], buf[i]
+	}
+}
@@ -0,0 +1,23 @@
+package spi_test
+
+import (
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+)
+
+func BenchmarkSwapBytes16(b *testing.B) {
+	buf := make([]byte, 4096)
+	b.SetBytes(int64(len(buf)))
+	for i := 0; i < b.N; i++ {
+		spi.SwapBytes16(buf)
+	}
+}
+
+func BenchmarkSwapBytes32(b *testing.B) {
+	buf := make([]byte, 4096)
+	b.SetBytes(int64(len(buf)))
+	for i := 0; i < b.N; i++ {
+		spi.SwapBytes32(buf)
+	}
+}
@@ -0,0 +1,33 @@
+package spi
+
+import (
+	"encoding/binary"
+)
+
+// WordSizeConfigurator is an optional interface implemented by devices that
+// can transfer words of some size other than eight bits.
+//
+// When the word size is not eight bits, each word occupies the smallest
+// whole number of bytes that can contain it in the buffers passed to
+// Write, Read, Exchange and Request. For example, 12-bit words each occupy
+// two bytes. The word's value is right-aligned within those bytes and the
+// unused most significant bits are ignored on write and undefined on read.
+// Implementations therefore never need to deal with a partial final byte;
+// buffers whose lengths are not a multiple of the per-word byte count
+// are rejected with an error.
+type WordSizeConfigurator interface {
+	// SetBitsPerWord sets the number of bits in each word transferred.
+	//
+	// Implementations return ErrNotSupported for sizes they cannot
+	// handle, rather than silently truncating or padding words.
+	SetBitsPerWord(bits uint8) error
+
+	// SetWordByteOrder sets the order in which the bytes of each
+	// multi-byte word are stored in the buffers passed to the transfer
+	// methods. The default is controller-specific; on Linux it is the
+	// CPU's native byte order.
+	//
+	// This has no effect while the word size is eight bits or fewer.
+	// Implementations that cannot control it return ErrNotSupported.
+	SetWordByteOrder(order binary.ByteOrder) error
+}
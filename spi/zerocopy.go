@@ -0,0 +1,26 @@
+package spi
+
+// ZeroCopyExchanger is an optional interface implemented by devices that
+// can perform a full-duplex exchange using a single buffer for both
+// directions, avoiding the second buffer and copy that Exchange requires.
+//
+// ZeroCopyExchange sends the contents of buf and overwrites buf in place
+// with the data received, so the data sent is lost once it returns.
+type ZeroCopyExchanger interface {
+	ZeroCopyExchange(buf []byte) error
+}
+
+// ZeroCopyExchange sends the contents of buf to the device while
+// overwriting buf in place with the data received.
+//
+// If d implements ZeroCopyExchanger then its native support is used.
+// Otherwise this falls back to Exchange using a temporary copy of buf,
+// which gives the same result without the performance benefit.
+func ZeroCopyExchange(d Device, buf []byte) error {
+	if zc, ok := d.(ZeroCopyExchanger); ok {
+		return zc.ZeroCopyExchange(buf)
+	}
+	out := append([]byte(nil), buf...)
+	_, err := d.Exchange(out, buf)
+	return err
+}
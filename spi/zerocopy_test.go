@@ -0,0 +1,48 @@
+package spi_test
+
+import (
+	"testing"
+
+	"github.com/apparentlymart/go-spi/spi"
+)
+
+// zeroCopyDevice adds a native ZeroCopyExchanger implementation to
+// unsafeDevice.
+type zeroCopyDevice struct {
+	*unsafeDevice
+}
+
+func (d zeroCopyDevice) ZeroCopyExchange(buf []byte) error {
+	_, err := d.Exchange(buf, buf)
+	return err
+}
+
+func BenchmarkZeroCopyExchange(b *testing.B) {
+	buf := make([]byte, 256)
+
+	b.Run("Exchange", func(b *testing.B) {
+		d := &unsafeDevice{}
+		in := make([]byte, len(buf))
+		b.SetBytes(int64(len(buf)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			d.Exchange(buf, in)
+		}
+	})
+	b.Run("native", func(b *testing.B) {
+		d := zeroCopyDevice{&unsafeDevice{}}
+		b.SetBytes(int64(len(buf)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			spi.ZeroCopyExchange(d, buf)
+		}
+	})
+	b.Run("fallback", func(b *testing.B) {
+		d := &unsafeDevice{}
+		b.SetBytes(int64(len(buf)))
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			spi.ZeroCopyExchange(d, buf)
+		}
+	})
+}